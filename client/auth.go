@@ -0,0 +1,148 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// ErrOTPRequired - the account has two-factor authentication enabled and the
+// credentials did not include a one-time password
+var ErrOTPRequired = errors.New("a one-time password is required")
+
+// Credentials - what `aptible login` exchanges for a token
+type Credentials struct {
+	Email    string
+	Password string
+	// OTP - one-time password, only needed for accounts with two-factor auth
+	OTP string
+	// Lifetime - how long the token should stay valid, the server may shorten it
+	Lifetime time.Duration
+}
+
+// AuthToken - the token issued by the auth server
+type AuthToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+type tokenRequest struct {
+	GrantType string `json:"grant_type"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	Scope     string `json:"scope"`
+	ExpiresIn int    `json:"expires_in,omitempty"`
+	OTPToken  string `json:"otp_token,omitempty"`
+}
+
+// AuthClient - talks to the auth server, e.g. auth.aptible.com
+type AuthClient struct {
+	base       url.URL
+	httpClient *http.Client
+}
+
+// NewAuthClient - same options as NewClient, only the scheme, network and
+// debug output apply since auth requests are never retried, limited or recorded
+func NewAuthClient(debug bool, domain string, opts ...Option) *AuthClient {
+	c := &client{
+		debug:    debug,
+		scheme:   "https",
+		debugOut: os.Stderr,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	var transport http.RoundTripper
+	base, err := c.network.Transport()
+	if err != nil {
+		transport = errTransport{err: fmt.Errorf("invalid network settings: %w", err)}
+	} else {
+		transport = base
+	}
+	if c.debug || c.harPath != "" {
+		var out io.Writer
+		if c.debug {
+			out = c.debugOut
+		}
+		transport = newDebugTransport(transport, out, c.harPath)
+	}
+
+	scheme, host := SplitAPIDomain(domain, c.scheme)
+	return &AuthClient{
+		base:       url.URL{Scheme: scheme, Host: host},
+		httpClient: &http.Client{Transport: transport},
+	}
+}
+
+// URL - the auth server's url, tokens.json is keyed by it
+func (a *AuthClient) URL() string {
+	return a.base.String()
+}
+
+// CreateToken - exchanges an email and password for a token, returns
+// ErrOTPRequired when the account needs a one-time password as well
+func (a *AuthClient) CreateToken(ctx context.Context, creds Credentials) (*AuthToken, error) {
+	body, err := json.Marshal(tokenRequest{
+		GrantType: "password",
+		Username:  creds.Email,
+		Password:  creds.Password,
+		Scope:     "manage",
+		ExpiresIn: int(creds.Lifetime.Seconds()),
+		OTPToken:  creds.OTP,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := a.base
+	endpoint.Path = "/tokens"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	r, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	respBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.StatusCode >= 400 {
+		var parsed errorBody
+		if json.Unmarshal(respBody, &parsed) == nil && parsed.Error == "otp_token_required" {
+			return nil, ErrOTPRequired
+		}
+		apiErr := &APIError{
+			Kind:       kindFromStatus(r.StatusCode),
+			StatusCode: r.StatusCode,
+			RequestID:  requestID(r),
+			Err:        fmt.Errorf("%s %s: %s", req.Method, endpoint.String(), r.Status),
+		}
+		decodeErrorBody(respBody, apiErr)
+		return nil, apiErr
+	}
+
+	var token AuthToken
+	if err := json.Unmarshal(respBody, &token); err != nil {
+		return nil, fmt.Errorf("unexpected response from %s: %w", a.URL(), err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("%s did not return a token", a.URL())
+	}
+	return &token, nil
+}
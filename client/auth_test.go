@@ -0,0 +1,114 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aptible/cloud-cli/client"
+	"github.com/aptible/cloud-cli/client/fake"
+)
+
+func TestCreateToken(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	auth := &fake.AuthServer{Email: "ops@example.com", Password: "hunter2", Now: func() time.Time { return now }}
+	twoFactor := &fake.AuthServer{Email: "ops@example.com", Password: "hunter2", OTP: "123456", Now: func() time.Time { return now }}
+
+	tests := []struct {
+		name         string
+		server       *fake.AuthServer
+		creds        client.Credentials
+		wantErr      error
+		wantKind     client.ErrorKind
+		wantLifetime time.Duration
+	}{
+		{
+			name:         "password",
+			server:       auth,
+			creds:        client.Credentials{Email: "ops@example.com", Password: "hunter2"},
+			wantLifetime: fake.DefaultTokenLifetime,
+		},
+		{
+			name:         "lifetime",
+			server:       auth,
+			creds:        client.Credentials{Email: "ops@example.com", Password: "hunter2", Lifetime: time.Hour},
+			wantLifetime: time.Hour,
+		},
+		{
+			name:     "wrong password",
+			server:   auth,
+			creds:    client.Credentials{Email: "ops@example.com", Password: "hunter3"},
+			wantKind: client.KindUnauthorized,
+		},
+		{
+			name:    "otp missing",
+			server:  twoFactor,
+			creds:   client.Credentials{Email: "ops@example.com", Password: "hunter2"},
+			wantErr: client.ErrOTPRequired,
+		},
+		{
+			name:     "otp wrong",
+			server:   twoFactor,
+			creds:    client.Credentials{Email: "ops@example.com", Password: "hunter2", OTP: "000000"},
+			wantKind: client.KindUnauthorized,
+		},
+		{
+			name:         "otp",
+			server:       twoFactor,
+			creds:        client.Credentials{Email: "ops@example.com", Password: "hunter2", OTP: "123456"},
+			wantLifetime: fake.DefaultTokenLifetime,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.server)
+			defer server.Close()
+
+			ac := client.NewAuthClient(false, server.URL, client.WithScheme("http"))
+			token, err := ac.CreateToken(context.Background(), tt.creds)
+			if tt.wantErr != nil || tt.wantKind != client.KindUnknown {
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				var apiErr *client.APIError
+				if tt.wantKind != client.KindUnknown && (!errors.As(err, &apiErr) || apiErr.Kind != tt.wantKind) {
+					t.Fatalf("err = %v, want kind %d", err, tt.wantKind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims, err := client.ParseClaims(token.AccessToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.Email != tt.creds.Email {
+				t.Errorf("email = %q, want %q", claims.Email, tt.creds.Email)
+			}
+			if got := claims.ExpiresIn(now); got != tt.wantLifetime {
+				t.Errorf("token expires in %s, want %s", got, tt.wantLifetime)
+			}
+			if !strings.HasPrefix(claims.Issuer, "http://") {
+				t.Errorf("issuer = %q", claims.Issuer)
+			}
+		})
+	}
+}
+
+func TestCreateTokenNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	ac := client.NewAuthClient(false, server.URL, client.WithScheme("http"))
+	_, err := ac.CreateToken(context.Background(), client.Credentials{Email: "ops@example.com", Password: "hunter2"})
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) || apiErr.Kind != client.KindNotFound {
+		t.Errorf("err = %v, want a not found api error", err)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Package cache
+A client.CloudClient decorator that keeps slow-changing lookups, like the
+organizations and environments offered by interactive prompts, on disk for a
+short while.  Calls that mutate a resource drop the cached lookups they affect.
+*/
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// DefaultTTLs - how long each cached method is trusted
+var DefaultTTLs = map[string]time.Duration{
+	"ListOrgs":         10 * time.Minute,
+	"ListEnvironments": 5 * time.Minute,
+	"ListAssetBundles": time.Hour,
+	// assets change status on their own, keep this short
+	"ListAssets": 30 * time.Second,
+}
+
+// Client - caching decorator, methods it does not override go straight to the api
+type Client struct {
+	client.CloudClient
+
+	dir  string
+	ttls map[string]time.Duration
+	now  func() time.Time
+}
+
+var _ client.CloudClient = (*Client)(nil)
+
+type entry struct {
+	ExpiresAt time.Time       `json:"expires_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// New - wraps cc, entries are stored under dir in a folder per namespace so
+// that different api domains and accounts never share entries
+func New(cc client.CloudClient, dir string, namespace string) *Client {
+	sum := sha256.Sum256([]byte(namespace))
+	return &Client{
+		CloudClient: cc,
+		dir:         filepath.Join(dir, hex.EncodeToString(sum[:8])),
+		ttls:        DefaultTTLs,
+		now:         time.Now,
+	}
+}
+
+// Clear - removes every cached entry for every namespace under dir
+func Clear(dir string) error {
+	return os.RemoveAll(dir)
+}
+
+func (c *Client) path(method string, args ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(args, "\x00")))
+	return filepath.Join(c.dir, method, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *Client) read(path string, out interface{}) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false
+	}
+	if c.now().After(e.ExpiresAt) {
+		return false
+	}
+	return json.Unmarshal(e.Value, out) == nil
+}
+
+// write - failures are ignored, the cache is only ever an optimization
+func (c *Client) write(path string, ttl time.Duration, value interface{}) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(entry{ExpiresAt: c.now().Add(ttl), Value: raw})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+
+	// write then rename so concurrent readers never see a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Invalidate - drops the cached result of method for the given arguments
+func (c *Client) Invalidate(method string, args ...string) {
+	os.Remove(c.path(method, args...))
+}
+
+func cached[T any](c *Client, method string, fetch func() (T, error), args ...string) (T, error) {
+	ttl, ok := c.ttls[method]
+	if !ok || ttl <= 0 {
+		return fetch()
+	}
+
+	path := c.path(method, args...)
+	var value T
+	if c.read(path, &value) {
+		return value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+	c.write(path, ttl, value)
+	return value, nil
+}
+
+func (c *Client) ListOrgs(ctx context.Context) ([]cac.OrganizationOutput, error) {
+	return cached(c, "ListOrgs", func() ([]cac.OrganizationOutput, error) {
+		return c.CloudClient.ListOrgs(ctx)
+	})
+}
+
+func (c *Client) ListEnvironments(ctx context.Context, orgId string) ([]cac.EnvironmentOutput, error) {
+	return cached(c, "ListEnvironments", func() ([]cac.EnvironmentOutput, error) {
+		return c.CloudClient.ListEnvironments(ctx, orgId)
+	}, orgId)
+}
+
+func (c *Client) ListAssetBundles(ctx context.Context, orgId, envId string) ([]cac.AssetBundle, error) {
+	return cached(c, "ListAssetBundles", func() ([]cac.AssetBundle, error) {
+		return c.CloudClient.ListAssetBundles(ctx, orgId, envId)
+	}, orgId, envId)
+}
+
+func (c *Client) ListAssets(ctx context.Context, orgId, envId string) ([]cac.AssetOutput, error) {
+	return cached(c, "ListAssets", func() ([]cac.AssetOutput, error) {
+		return c.CloudClient.ListAssets(ctx, orgId, envId)
+	}, orgId, envId)
+}
+
+func (c *Client) CreateOrg(ctx context.Context, params cac.OrganizationInput) (*cac.OrganizationOutput, error) {
+	defer c.Invalidate("ListOrgs")
+	return c.CloudClient.CreateOrg(ctx, params)
+}
+
+func (c *Client) UpdateOrg(ctx context.Context, orgId string, params cac.OrganizationInput) (*cac.OrganizationOutput, error) {
+	defer c.Invalidate("ListOrgs")
+	return c.CloudClient.UpdateOrg(ctx, orgId, params)
+}
+
+func (c *Client) CreateEnvironment(ctx context.Context, orgId string, params cac.EnvironmentInput) (*cac.EnvironmentOutput, error) {
+	defer c.Invalidate("ListEnvironments", orgId)
+	return c.CloudClient.CreateEnvironment(ctx, orgId, params)
+}
+
+func (c *Client) UpdateEnvironment(ctx context.Context, orgId, envId string, params cac.EnvironmentInput) (*cac.EnvironmentOutput, error) {
+	defer c.Invalidate("ListEnvironments", orgId)
+	return c.CloudClient.UpdateEnvironment(ctx, orgId, envId, params)
+}
+
+func (c *Client) DestroyEnvironment(ctx context.Context, orgId, envId string) error {
+	defer c.Invalidate("ListEnvironments", orgId)
+	defer c.Invalidate("ListAssets", orgId, envId)
+	defer c.Invalidate("ListAssetBundles", orgId, envId)
+	return c.CloudClient.DestroyEnvironment(ctx, orgId, envId)
+}
+
+func (c *Client) CreateAsset(ctx context.Context, orgId, envId string, params cac.AssetInput) (*cac.AssetOutput, error) {
+	defer c.Invalidate("ListAssets", orgId, envId)
+	return c.CloudClient.CreateAsset(ctx, orgId, envId, params)
+}
+
+func (c *Client) UpdateAsset(ctx context.Context, orgId, envId, assetId string, params cac.AssetInput) (*cac.AssetOutput, string, error) {
+	defer c.Invalidate("ListAssets", orgId, envId)
+	return c.CloudClient.UpdateAsset(ctx, orgId, envId, assetId, params)
+}
+
+func (c *Client) DestroyAsset(ctx context.Context, orgId, envId, assetId string) error {
+	defer c.Invalidate("ListAssets", orgId, envId)
+	return c.CloudClient.DestroyAsset(ctx, orgId, envId, assetId)
+}
+
+// connections are embedded in the assets they belong to
+func (c *Client) CreateConnection(ctx context.Context, orgId, envId, assetId string, params cac.ConnectionInput) (*cac.ConnectionOutput, error) {
+	defer c.Invalidate("ListAssets", orgId, envId)
+	return c.CloudClient.CreateConnection(ctx, orgId, envId, assetId, params)
+}
+
+func (c *Client) DestroyConnection(ctx context.Context, orgId, envId, assetId, connectionId string) error {
+	defer c.Invalidate("ListAssets", orgId, envId)
+	return c.CloudClient.DestroyConnection(ctx, orgId, envId, assetId, connectionId)
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+
+	"github.com/aptible/cloud-cli/client/fake"
+)
+
+// newCache - a cache over a seeded fake with a clock the test moves
+func newCache(t *testing.T) (*Client, *fake.Client, *time.Time) {
+	f := fake.NewSeeded()
+	now := time.Now()
+	c := New(f, t.TempDir(), "https://api.example.com\x00user-1")
+	c.now = func() time.Time { return now }
+	return c, f, &now
+}
+
+func envNames(t *testing.T, c *Client) []string {
+	envs, err := c.ListEnvironments(context.Background(), fake.DemoOrgId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{}
+	for _, env := range envs {
+		names = append(names, env.Name)
+	}
+	return names
+}
+
+func TestCacheTTL(t *testing.T) {
+	ttl := DefaultTTLs["ListEnvironments"]
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		// fresh - whether an environment created behind the cache's back shows up
+		fresh bool
+	}{
+		{name: "within ttl", elapsed: ttl / 2, fresh: false},
+		{name: "at ttl", elapsed: ttl, fresh: false},
+		{name: "after ttl", elapsed: ttl + time.Second, fresh: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, f, now := newCache(t)
+			if got := envNames(t, c); len(got) != 1 {
+				t.Fatalf("environments = %v, want the demo one", got)
+			}
+			if _, err := f.CreateEnvironment(context.Background(), fake.DemoOrgId, cac.EnvironmentInput{Name: "staging"}); err != nil {
+				t.Fatal(err)
+			}
+
+			*now = now.Add(tt.elapsed)
+			got := envNames(t, c)
+			if fresh := len(got) == 2; fresh != tt.fresh {
+				t.Errorf("environments = %v after %s, fresh = %v, want %v", got, tt.elapsed, fresh, tt.fresh)
+			}
+		})
+	}
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(c *Client, f *fake.Client) error
+		fresh  bool
+	}{
+		{
+			name: "create through the cache",
+			mutate: func(c *Client, f *fake.Client) error {
+				_, err := c.CreateEnvironment(context.Background(), fake.DemoOrgId, cac.EnvironmentInput{Name: "staging"})
+				return err
+			},
+			fresh: true,
+		},
+		{
+			name: "invalidate",
+			mutate: func(c *Client, f *fake.Client) error {
+				_, err := f.CreateEnvironment(context.Background(), fake.DemoOrgId, cac.EnvironmentInput{Name: "staging"})
+				c.Invalidate("ListEnvironments", fake.DemoOrgId)
+				return err
+			},
+			fresh: true,
+		},
+		{
+			name: "invalidate another org",
+			mutate: func(c *Client, f *fake.Client) error {
+				_, err := f.CreateEnvironment(context.Background(), fake.DemoOrgId, cac.EnvironmentInput{Name: "staging"})
+				c.Invalidate("ListEnvironments", "00000000-0000-4000-8000-0000000000ff")
+				return err
+			},
+			fresh: false,
+		},
+		{
+			name: "invalidate another method",
+			mutate: func(c *Client, f *fake.Client) error {
+				_, err := f.CreateEnvironment(context.Background(), fake.DemoOrgId, cac.EnvironmentInput{Name: "staging"})
+				c.Invalidate("ListOrgs")
+				return err
+			},
+			fresh: false,
+		},
+		{
+			name: "clear",
+			mutate: func(c *Client, f *fake.Client) error {
+				_, err := f.CreateEnvironment(context.Background(), fake.DemoOrgId, cac.EnvironmentInput{Name: "staging"})
+				if err != nil {
+					return err
+				}
+				return Clear(c.dir)
+			},
+			fresh: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, f, _ := newCache(t)
+			envNames(t, c)
+			if err := tt.mutate(c, f); err != nil {
+				t.Fatal(err)
+			}
+			got := envNames(t, c)
+			if fresh := len(got) == 2; fresh != tt.fresh {
+				t.Errorf("environments = %v, fresh = %v, want %v", got, fresh, tt.fresh)
+			}
+		})
+	}
+}
+
+func TestCacheNamespaces(t *testing.T) {
+	dir := t.TempDir()
+	f := fake.NewSeeded()
+	first := New(f, dir, "https://api.example.com\x00user-1")
+	second := New(f, dir, "https://api.example.com\x00user-2")
+
+	envNames(t, first)
+	if _, err := f.CreateEnvironment(context.Background(), fake.DemoOrgId, cac.EnvironmentInput{Name: "staging"}); err != nil {
+		t.Fatal(err)
+	}
+	// another account never sees the first one's entries
+	if got := envNames(t, second); len(got) != 2 {
+		t.Errorf("second namespace got %v, want both environments", got)
+	}
+	if got := envNames(t, first); len(got) != 1 {
+		t.Errorf("first namespace got %v, want its cached environment", got)
+	}
+}
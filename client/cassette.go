@@ -0,0 +1,213 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CassetteMode - whether api traffic is recorded to or replayed from a cassette
+type CassetteMode string
+
+const (
+	CassetteOff    CassetteMode = ""
+	CassetteRecord CassetteMode = "record"
+	CassetteReplay CassetteMode = "replay"
+)
+
+// ParseCassetteMode - validates the value of APTIBLE_CASSETTE
+func ParseCassetteMode(mode string) (CassetteMode, error) {
+	switch CassetteMode(strings.ToLower(mode)) {
+	case CassetteOff:
+		return CassetteOff, nil
+	case CassetteRecord:
+		return CassetteRecord, nil
+	case CassetteReplay:
+		return CassetteReplay, nil
+	default:
+		return CassetteOff, fmt.Errorf("unknown cassette mode %q, expected record or replay", mode)
+	}
+}
+
+// Cassette - recorded api traffic, secrets are scrubbed before anything is written
+type Cassette struct {
+	Interactions []Interaction `yaml:"interactions"`
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `yaml:"request"`
+	Response RecordedResponse `yaml:"response"`
+}
+
+type RecordedRequest struct {
+	Method string `yaml:"method"`
+	Path   string `yaml:"path"`
+	Query  string `yaml:"query,omitempty"`
+	Body   string `yaml:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	Status  int                 `yaml:"status"`
+	Headers map[string][]string `yaml:"headers,omitempty"`
+	Body    string              `yaml:"body,omitempty"`
+}
+
+// LoadCassette - reads a cassette from disk
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if err := yaml.Unmarshal(data, cassette); err != nil {
+		return nil, fmt.Errorf("unable to parse cassette %s: %w", path, err)
+	}
+	return cassette, nil
+}
+
+// Save - writes the cassette to disk
+func (c *Cassette) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// normalizeBody - scrubbed, canonical form of a body used for recording and
+// matching, json is re-encoded so key order and whitespace do not matter
+func normalizeBody(body []byte) string {
+	redacted := RedactBody(body)
+	var parsed interface{}
+	if err := json.Unmarshal(redacted, &parsed); err != nil {
+		return string(redacted)
+	}
+	canonical, err := json.Marshal(parsed)
+	if err != nil {
+		return string(redacted)
+	}
+	return string(canonical)
+}
+
+func recordRequest(req *http.Request, body []byte) RecordedRequest {
+	return RecordedRequest{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  redactQuery(req.URL.Query()).Encode(),
+		Body:   normalizeBody(body),
+	}
+}
+
+func (r RecordedRequest) matches(other RecordedRequest) bool {
+	return r.Method == other.Method &&
+		r.Path == other.Path &&
+		r.Query == other.Query &&
+		r.Body == other.Body
+}
+
+// cassetteTransport - http.RoundTripper that records every round trip or
+// answers them from a previously recorded cassette
+type cassetteTransport struct {
+	base http.RoundTripper
+	mode CassetteMode
+	path string
+
+	load     sync.Once
+	loadErr  error
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+func newCassetteTransport(base http.RoundTripper, mode CassetteMode, path string) *cassetteTransport {
+	return &cassetteTransport{base: base, mode: mode, path: path, cassette: &Cassette{}}
+}
+
+func (t *cassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded := recordRequest(req, requestBody(req))
+	if t.mode == CassetteReplay {
+		// loaded on first use so NewClient does not have to fail
+		t.load.Do(func() {
+			t.cassette, t.loadErr = LoadCassette(t.path)
+			if t.loadErr == nil {
+				t.used = make([]bool, len(t.cassette.Interactions))
+			}
+		})
+		if t.loadErr != nil {
+			return nil, t.loadErr
+		}
+		return t.replay(req, recorded)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	body := readBody(&resp.Body)
+
+	headers := map[string][]string{}
+	for name, values := range RedactHeaders(resp.Header) {
+		headers[name] = values
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cassette.Interactions = append(t.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: headers,
+			Body:    string(RedactBody(body)),
+		},
+	})
+	// saved after every interaction so the cassette survives an early exit
+	if err := t.cassette.Save(t.path); err != nil {
+		return nil, fmt.Errorf("unable to save cassette %s: %w", t.path, err)
+	}
+
+	return resp, nil
+}
+
+// replay - answers with the first unused interaction that matches, so a
+// request repeated while polling gets its recorded responses in order
+func (t *cassetteTransport) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.cassette.Interactions {
+		if t.used[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+		t.used[i] = true
+
+		headers := http.Header{}
+		for name, values := range interaction.Response.Headers {
+			headers[name] = values
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.Status, http.StatusText(interaction.Response.Status)),
+			StatusCode:    interaction.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        headers,
+			Body:          io.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf(
+		"cassette %s has no unused interaction for %s %s",
+		t.path,
+		recorded.Method,
+		strings.TrimSuffix(recorded.Path+"?"+recorded.Query, "?"),
+	)
+}
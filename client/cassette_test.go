@@ -0,0 +1,139 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestCassetteRecordReplay(t *testing.T) {
+	file := path.Join(t.TempDir(), "cassette.yaml")
+
+	// a status that moves on every poll, like an asset being provisioned
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/assets/1":
+			polls++
+			if polls == 1 {
+				io.WriteString(w, `{"status":"PENDING"}`)
+			} else {
+				io.WriteString(w, `{"status":"DEPLOYED"}`)
+			}
+		case r.Method == "POST" && r.URL.Path == "/assets":
+			w.Header().Set("Set-Cookie", "session=secret")
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, `{"id":"1"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	type call struct {
+		method string
+		url    string
+		body   string
+	}
+	calls := []call{
+		{method: "POST", url: "/assets", body: `{"name":"db","size":1}`},
+		{method: "GET", url: "/assets/1"},
+		{method: "GET", url: "/assets/1"},
+		{method: "GET", url: "/missing?page=2"},
+	}
+	do := func(transport http.RoundTripper, base string, c call) (*http.Response, string, error) {
+		var body io.Reader
+		if c.body != "" {
+			body = strings.NewReader(c.body)
+		}
+		req, err := http.NewRequest(c.method, base+c.url, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", "Bearer secret-token")
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		text, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, string(text), nil
+	}
+
+	recorder := newCassetteTransport(http.DefaultTransport, CassetteRecord, file)
+	recorded := []string{}
+	for _, c := range calls {
+		resp, body, err := do(recorder, server.URL, c)
+		if err != nil {
+			t.Fatalf("%s %s: %s", c.method, c.url, err)
+		}
+		recorded = append(recorded, resp.Status+" "+body)
+	}
+	server.Close()
+
+	cassette, err := LoadCassette(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cassette.Interactions) != len(calls) {
+		t.Fatalf("recorded %d interactions, want %d", len(cassette.Interactions), len(calls))
+	}
+	if headers := cassette.Interactions[0].Response.Headers["Set-Cookie"]; len(headers) != 1 || headers[0] != Redacted {
+		t.Errorf("Set-Cookie was recorded as %v", headers)
+	}
+
+	// replay never reaches the network, the server is gone
+	player := newCassetteTransport(http.DefaultTransport, CassetteReplay, file)
+	replayed := []string{}
+	for _, c := range calls {
+		resp, body, err := do(player, "http://replay.invalid", c)
+		if err != nil {
+			t.Fatalf("%s %s: %s", c.method, c.url, err)
+		}
+		replayed = append(replayed, resp.Status+" "+body)
+	}
+	for i := range calls {
+		if replayed[i] != recorded[i] {
+			t.Errorf("%s %s replayed %q, recorded %q", calls[i].method, calls[i].url, replayed[i], recorded[i])
+		}
+	}
+
+	tests := []struct {
+		name string
+		call call
+	}{
+		{name: "every recording used up", call: calls[1]},
+		{name: "other body", call: call{method: "POST", url: "/assets", body: `{"name":"cache"}`}},
+		{name: "other query", call: call{method: "GET", url: "/missing?page=3"}},
+		{name: "other method", call: call{method: "DELETE", url: "/assets/1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := do(player, "http://replay.invalid", tt.call); err == nil {
+				t.Errorf("%s %s replayed, want an error", tt.call.method, tt.call.url)
+			}
+		})
+	}
+
+	// json bodies match whatever their key order
+	again := newCassetteTransport(http.DefaultTransport, CassetteReplay, file)
+	if _, _, err := do(again, "http://replay.invalid", call{method: "POST", url: "/assets", body: `{ "size": 1, "name": "db" }`}); err != nil {
+		t.Errorf("reordered body did not match: %s", err)
+	}
+}
+
+func TestCassetteReplayMissingFile(t *testing.T) {
+	player := newCassetteTransport(http.DefaultTransport, CassetteReplay, path.Join(t.TempDir(), "missing.yaml"))
+	req, err := http.NewRequest("GET", "http://replay.invalid/organizations", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := player.RoundTrip(req); err == nil {
+		t.Error("replay without a cassette succeeded")
+	}
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// ErrNotJWT - the token is not a JWT, e.g. a test token, so nothing is known about it
+var ErrNotJWT = errors.New("token is not a JWT")
+
+// Claims - what the cli reads from a token, the signature is not verified
+// since only the api has to trust it
+type Claims struct {
+	Subject   string
+	Issuer    string
+	Email     string
+	Scope     string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type rawClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Email     string          `json:"email"`
+	Scope     string          `json:"scope"`
+	IssuedAt  json.RawMessage `json:"iat"`
+	ExpiresAt json.RawMessage `json:"exp"`
+}
+
+// ParseClaims - decodes the payload of a JWT
+func ParseClaims(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrNotJWT
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotJWT, err)
+	}
+
+	var raw rawClaims
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotJWT, err)
+	}
+	return &Claims{
+		Subject:   raw.Subject,
+		Issuer:    raw.Issuer,
+		Email:     raw.Email,
+		Scope:     raw.Scope,
+		IssuedAt:  numericDate(raw.IssuedAt),
+		ExpiresAt: numericDate(raw.ExpiresAt),
+	}, nil
+}
+
+// numericDate - seconds since the epoch, sometimes sent with a fraction
+func numericDate(raw json.RawMessage) time.Time {
+	var seconds float64
+	if len(raw) == 0 || json.Unmarshal(raw, &seconds) != nil {
+		return time.Time{}
+	}
+	// whole seconds and the fraction apart, in nanoseconds the far future
+	// expiries some tokens use, e.g. 9999999999, overflow an int64
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9))
+}
+
+// Expires - whether the token has an expiry at all
+func (c *Claims) Expires() bool {
+	return !c.ExpiresAt.IsZero()
+}
+
+// ExpiresIn - negative once the token expired, 0 when it never does
+func (c *Claims) ExpiresIn(now time.Time) time.Duration {
+	if !c.Expires() {
+		return 0
+	}
+	return c.ExpiresAt.Sub(now)
+}
+
+// Expired - tokens without an expiry never expire
+func (c *Claims) Expired(now time.Time) bool {
+	return c.Expires() && !now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+)
+
+// jwt - an unsigned token carrying payload, the cli never checks signatures
+func jwt(payload string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(payload)) + ".sig"
+}
+
+func TestParseClaims(t *testing.T) {
+	tests := []struct {
+		name        string
+		token       string
+		wantErr     error
+		wantSubject string
+		wantEmail   string
+		wantExpires time.Time
+	}{
+		{
+			name:        "claims",
+			token:       jwt(`{"sub":"user-1","email":"ops@example.com","iat":1700000000,"exp":1700003600}`),
+			wantSubject: "user-1",
+			wantEmail:   "ops@example.com",
+			wantExpires: time.Unix(1700003600, 0),
+		},
+		{
+			name:        "fractional expiry",
+			token:       jwt(`{"exp":1700003600.5}`),
+			wantExpires: time.Unix(1700003600, 500000000),
+		},
+		{
+			name:        "far future expiry",
+			token:       jwt(`{"exp":9999999999}`),
+			wantExpires: time.Unix(9999999999, 0),
+		},
+		{name: "no expiry", token: jwt(`{"sub":"user-1"}`), wantSubject: "user-1"},
+		{name: "expiry that is not a number", token: jwt(`{"exp":"tomorrow"}`)},
+		{name: "opaque token", token: "not-a-jwt", wantErr: ErrNotJWT},
+		{name: "payload that is not base64", token: "a.!!!.c", wantErr: ErrNotJWT},
+		{name: "payload that is not json", token: "a." + base64.RawURLEncoding.EncodeToString([]byte("nope")) + ".c", wantErr: ErrNotJWT},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := ParseClaims(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if claims.Subject != tt.wantSubject || claims.Email != tt.wantEmail {
+				t.Errorf("subject, email = %q, %q, want %q, %q", claims.Subject, claims.Email, tt.wantSubject, tt.wantEmail)
+			}
+			if !claims.ExpiresAt.Equal(tt.wantExpires) {
+				t.Errorf("expires at %s, want %s", claims.ExpiresAt, tt.wantExpires)
+			}
+			if claims.Expires() != !tt.wantExpires.IsZero() {
+				t.Errorf("Expires() = %v", claims.Expires())
+			}
+		})
+	}
+
+	far, err := ParseClaims(jwt(`{"exp":9999999999}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if year := far.ExpiresAt.UTC().Year(); year != 2286 {
+		t.Errorf("exp 9999999999 is in %d, want 2286", year)
+	}
+}
+
+func TestClaimsExpiry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name        string
+		expiresAt   time.Time
+		wantExpired bool
+		wantIn      time.Duration
+	}{
+		{name: "never", expiresAt: time.Time{}, wantExpired: false, wantIn: 0},
+		{name: "later", expiresAt: now.Add(time.Hour), wantExpired: false, wantIn: time.Hour},
+		{name: "now", expiresAt: now, wantExpired: true, wantIn: 0},
+		{name: "earlier", expiresAt: now.Add(-time.Minute), wantExpired: true, wantIn: -time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &Claims{ExpiresAt: tt.expiresAt}
+			if got := claims.Expired(now); got != tt.wantExpired {
+				t.Errorf("Expired = %v, want %v", got, tt.wantExpired)
+			}
+			if got := claims.ExpiresIn(now); got != tt.wantIn {
+				t.Errorf("ExpiresIn = %s, want %s", got, tt.wantIn)
+			}
+		})
+	}
+}
@@ -3,221 +3,474 @@ package client
 import (
 	"context"
 	"fmt"
-	"log"
+	"io"
 	"net/http"
-	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
 
 	cac "github.com/aptible/cloud-api-clients/clients/go"
 )
 
 // client - internal cac struct used only for this service with some common configuration
 type client struct {
-	ctx context.Context
-
 	apiClient *cac.APIClient
 	debug     bool
 	token     string
+	retry     RetryPolicy
+	rateLimit RateLimit
+	debugOut  io.Writer
+	harPath   string
+	scheme    string
+	network   Network
+
+	cassetteMode CassetteMode
+	cassettePath string
+}
+
+// Option - optional configuration for NewClient
+type Option func(*client)
+
+// WithRetryPolicy - overrides DefaultRetryPolicy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *client) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimit - overrides DefaultRateLimit
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *client) {
+		c.rateLimit = limit
+	}
+}
+
+// WithScheme - scheme used when the api domain is not a full url, defaults to https
+func WithScheme(scheme string) Option {
+	return func(c *client) {
+		c.scheme = scheme
+	}
+}
+
+// WithNetwork - proxy and TLS settings for every request
+func WithNetwork(network Network) Option {
+	return func(c *client) {
+		c.network = network
+	}
+}
+
+// WithDebugOutput - where --debug writes requests and responses, defaults to stderr
+func WithDebugOutput(w io.Writer) Option {
+	return func(c *client) {
+		c.debugOut = w
+	}
+}
+
+// WithHAR - records every request and response to a HAR file at path
+func WithHAR(path string) Option {
+	return func(c *client) {
+		c.harPath = path
+	}
+}
+
+// WithCassette - records api traffic to, or replays it from, the cassette at path
+func WithCassette(mode CassetteMode, path string) Option {
+	return func(c *client) {
+		c.cassetteMode = mode
+		c.cassettePath = path
+	}
 }
 
 // NewClient - generate a new cloud api cloud_api_client
-func NewClient(debug bool, host string, token string) CloudClient {
+func NewClient(debug bool, host string, token string, opts ...Option) CloudClient {
+	c := &client{
+		debug:     debug,
+		token:     token,
+		retry:     DefaultRetryPolicy(),
+		rateLimit: DefaultRateLimit(),
+		scheme:    "https",
+		debugOut:  os.Stderr,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
 	config := cac.NewConfiguration()
-	config.Host = host
-	config.Scheme = "https"
+	config.Scheme, config.Host = SplitAPIDomain(host, c.scheme)
 
-	apiClient := cac.NewAPIClient(config)
+	var transport http.RoundTripper
+	base, err := c.network.Transport()
+	if err != nil {
+		transport = errTransport{err: fmt.Errorf("invalid network settings: %w", err)}
+	} else {
+		transport = base
+	}
+	if c.debug {
+		target := &url.URL{Scheme: config.Scheme, Host: config.Host}
+		fmt.Fprintf(c.debugOut, "--- NETWORK %s ---\n%s\n\n", target, c.network.Describe(target))
+	}
+	if c.cassetteMode != CassetteOff {
+		transport = newCassetteTransport(transport, c.cassetteMode, c.cassettePath)
+	}
+	if c.debug || c.harPath != "" {
+		var out io.Writer
+		if c.debug {
+			out = c.debugOut
+		}
+		transport = newDebugTransport(transport, out, c.harPath)
+	}
+	// every attempt, retries included, waits on the same limiter
+	transport = newRateLimitTransport(transport, c.rateLimit)
+	// the retry transport wraps the debug transport so every attempt is logged
+	transport = newRetryTransport(transport, c.retry, c.PrintAttempt)
+	transport = newPageTransport(transport)
 
-	ctx := context.Background()
-	ctx = context.WithValue(ctx, cac.ContextAccessToken, token)
+	config.HTTPClient = &http.Client{
+		Transport: transport,
+	}
+
+	c.apiClient = cac.NewAPIClient(config)
 
-	return &client{
-		ctx:       ctx,
-		apiClient: apiClient,
+	return c
+}
 
-		debug: debug,
-		token: token,
+// SplitAPIDomain - the api domain may be a bare host, e.g. cloud-api.cloud.aptible.com,
+// or a full url such as http://localhost:8080 which overrides scheme
+func SplitAPIDomain(domain string, scheme string) (string, string) {
+	if strings.Contains(domain, "://") {
+		if u, err := url.Parse(domain); err == nil && u.Host != "" {
+			return u.Scheme, u.Host
+		}
+	}
+	if scheme == "" {
+		scheme = "https"
 	}
+	return scheme, strings.TrimSuffix(domain, "/")
+}
+
+// withAuth - attaches the bearer token to the context of a single request
+func (c *client) withAuth(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cac.ContextAccessToken, c.token)
 }
 
 func (c *client) HandleResponse(r *http.Response) {
 	if r == nil {
 		fmt.Printf("The HTTP response is nil which means the request was never made.  Are you sure your API domain is set properly? (%s)\n", c.apiClient.GetConfig().Host)
-		return
 	}
-	c.PrintResponse(r)
 }
 
-func (c *client) PrintResponse(r *http.Response) {
+// PrintAttempt - notes every attempt made by the retry transport in the debug output
+func (c *client) PrintAttempt(attempt int, attempts int, r *http.Response, err error) {
 	if !c.debug {
 		return
 	}
 
-	log.Println("--- DEBUG ---")
-	reqDump, err := httputil.DumpRequestOut(r.Request, false)
+	var outcome string
 	if err != nil {
-		fmt.Println(err)
+		outcome = err.Error()
+	} else {
+		outcome = r.Status
 	}
+	fmt.Fprintf(c.debugOut, "--- attempt %d/%d: %s ---\n\n", attempt, attempts, outcome)
+}
 
-	log.Printf("REQUEST:\n%s", string(reqDump))
+func (c *client) ListEnvironments(ctx context.Context, orgId string) ([]cac.EnvironmentOutput, error) {
+	return Collect(ctx, c.EnvironmentPages(orgId, PageOptions{}))
+}
 
-	respDump, err := httputil.DumpResponse(r, true)
-	if err != nil {
-		log.Println(err)
-	}
+func (c *client) EnvironmentPages(orgId string, opts PageOptions) *Pager[cac.EnvironmentOutput] {
+	links := &pageLinks{}
+	return NewPager(func(ctx context.Context, page Page) ([]cac.EnvironmentOutput, bool, error) {
+		request := c.
+			apiClient.
+			OrganizationsApi.
+			OrganizationGetEnvironments(c.withAuth(links.context(ctx)), orgId)
+		envs, r, err := request.Execute()
+		c.HandleResponse(r)
+		return envs, links.update(r), wrapError(r, err)
+	}, opts)
+}
 
-	log.Printf("RESPONSE:\n%s\n", string(respDump))
+func (c *client) CreateEnvironment(ctx context.Context, orgId string, params cac.EnvironmentInput) (*cac.EnvironmentOutput, error) {
+	request := c.
+		apiClient.
+		EnvironmentsApi.
+		EnvironmentCreate(c.withAuth(ctx), orgId).
+		EnvironmentInput(params)
+	env, r, err := request.Execute()
+	c.HandleResponse(r)
+	return env, wrapError(r, err)
 }
 
-func (c *client) ListEnvironments(orgId string) ([]cac.EnvironmentOutput, error) {
+func (c *client) DescribeEnvironment(ctx context.Context, orgId string, envId string) (*cac.EnvironmentOutput, error) {
 	request := c.
 		apiClient.
-		OrganizationsApi.
-		OrganizationGetEnvironments(c.ctx, orgId)
+		EnvironmentsApi.
+		EnvironmentGet(c.withAuth(ctx), envId, orgId)
 	env, r, err := request.Execute()
 	c.HandleResponse(r)
-	return env, err
+	return env, wrapError(r, err)
 }
 
-func (c *client) CreateEnvironment(orgId string, params cac.EnvironmentInput) (*cac.EnvironmentOutput, error) {
+// UpdateEnvironment - replaces the environment with params, unset fields are cleared
+func (c *client) UpdateEnvironment(ctx context.Context, orgId string, envId string, params cac.EnvironmentInput) (*cac.EnvironmentOutput, error) {
 	request := c.
 		apiClient.
 		EnvironmentsApi.
-		EnvironmentCreate(c.ctx, orgId).
+		EnvironmentUpdate(c.withAuth(ctx), envId, orgId).
 		EnvironmentInput(params)
 	env, r, err := request.Execute()
 	c.HandleResponse(r)
-	return env, err
+	return env, wrapError(r, err)
 }
 
-func (c *client) DestroyEnvironment(orgId string, envId string) error {
+func (c *client) DestroyEnvironment(ctx context.Context, orgId string, envId string) error {
 	_, r, err := c.
 		apiClient.
 		EnvironmentsApi.
 		EnvironmentDelete(
-			c.ctx,
+			c.withAuth(ctx),
 			envId,
 			orgId,
 		).
 		Execute()
 	c.HandleResponse(r)
-	return err
+	return wrapError(r, err)
 }
 
-func (c *client) CreateOrg(orgId string, params cac.OrganizationInput) (*cac.OrganizationOutput, error) {
+func (c *client) CreateOrg(ctx context.Context, params cac.OrganizationInput) (*cac.OrganizationOutput, error) {
 	request := c.
 		apiClient.
 		OrganizationsApi.
-		OrganizationUpdate(c.ctx, orgId).
+		OrganizationCreate(c.withAuth(ctx)).
 		OrganizationInput(params)
 	org, r, err := request.Execute()
 	c.HandleResponse(r)
-	return org, err
+	return org, wrapError(r, err)
 }
 
-func (c *client) FindOrg(orgId string) (*cac.OrganizationOutput, error) {
+// UpdateOrg - replaces the organization with params, unset fields are cleared
+func (c *client) UpdateOrg(ctx context.Context, orgId string, params cac.OrganizationInput) (*cac.OrganizationOutput, error) {
+	request := c.
+		apiClient.
+		OrganizationsApi.
+		OrganizationUpdate(c.withAuth(ctx), orgId).
+		OrganizationInput(params)
+	org, r, err := request.Execute()
+	c.HandleResponse(r)
+	return org, wrapError(r, err)
+}
+
+func (c *client) FindOrg(ctx context.Context, orgId string) (*cac.OrganizationOutput, error) {
 	org, r, err := c.
 		apiClient.
 		OrganizationsApi.
-		OrganizationGet(c.ctx, orgId).
+		OrganizationGet(c.withAuth(ctx), orgId).
 		Execute()
 	c.HandleResponse(r)
-	return org, err
+	return org, wrapError(r, err)
 }
 
-func (c *client) CreateAsset(orgId string, envId string, params cac.AssetInput) (*cac.AssetOutput, error) {
+func (c *client) CreateAsset(ctx context.Context, orgId string, envId string, params cac.AssetInput) (*cac.AssetOutput, error) {
 	request := c.
 		apiClient.
 		AssetsApi.
 		AssetCreate(
-			c.ctx,
+			c.withAuth(ctx),
 			envId,
 			orgId,
 		).
 		AssetInput(params)
 	asset, r, err := request.Execute()
 	c.HandleResponse(r)
-	return asset, err
+	return asset, wrapError(r, err)
 }
 
-func (c *client) DestroyAsset(orgId string, envId string, assetId string) error {
+func (c *client) UpdateAsset(ctx context.Context, orgId string, envId string, assetId string, params cac.AssetInput) (*cac.AssetOutput, string, error) {
+	request := c.
+		apiClient.
+		AssetsApi.
+		AssetUpdate(
+			c.withAuth(ctx),
+			assetId,
+			envId,
+			orgId,
+		).
+		AssetInput(params)
+	asset, r, err := request.Execute()
+	c.HandleResponse(r)
+	return asset, OperationId(r), wrapError(r, err)
+}
+
+// OperationId - the operation a request started, from an X-Operation-Id
+// header or a Location header pointing at the operation, empty when the
+// response names neither
+func OperationId(r *http.Response) string {
+	if r == nil {
+		return ""
+	}
+	if id := r.Header.Get("X-Operation-Id"); id != "" {
+		return id
+	}
+	location, err := url.Parse(r.Header.Get("Location"))
+	if err != nil {
+		return ""
+	}
+	segments := strings.Split(strings.Trim(location.Path, "/"), "/")
+	if n := len(segments); n >= 2 && segments[n-2] == "operations" {
+		return segments[n-1]
+	}
+	return ""
+}
+
+func (c *client) DestroyAsset(ctx context.Context, orgId string, envId string, assetId string) error {
 	request := c.
 		apiClient.
 		AssetsApi.
 		AssetDelete(
-			c.ctx,
+			c.withAuth(ctx),
 			assetId,
 			envId,
 			orgId,
 		)
 	_, r, err := request.Execute()
 	c.HandleResponse(r)
-	return err
+	return wrapError(r, err)
 }
 
-func (c *client) ListAssets(orgId string, envId string) ([]cac.AssetOutput, error) {
-	request := c.apiClient.EnvironmentsApi.EnvironmentGetAssets(
-		c.ctx,
-		envId,
-		orgId,
-	)
-	assets, r, err := request.Execute()
-	c.HandleResponse(r)
-	return assets, err
+func (c *client) ListAssets(ctx context.Context, orgId string, envId string) ([]cac.AssetOutput, error) {
+	return Collect(ctx, c.AssetPages(orgId, envId, PageOptions{}))
+}
+
+func (c *client) AssetPages(orgId string, envId string, opts PageOptions) *Pager[cac.AssetOutput] {
+	links := &pageLinks{}
+	return NewPager(func(ctx context.Context, page Page) ([]cac.AssetOutput, bool, error) {
+		request := c.apiClient.EnvironmentsApi.EnvironmentGetAssets(
+			c.withAuth(links.context(ctx)),
+			envId,
+			orgId,
+		)
+		assets, r, err := request.Execute()
+		c.HandleResponse(r)
+		return assets, links.update(r), wrapError(r, err)
+	}, opts)
 }
 
-func (c *client) DescribeAsset(orgId string, envId string, assetId string) (*cac.AssetOutput, error) {
+func (c *client) DescribeAsset(ctx context.Context, orgId string, envId string, assetId string) (*cac.AssetOutput, error) {
 	request := c.
 		apiClient.
 		AssetsApi.
 		AssetGet(
-			c.ctx,
+			c.withAuth(ctx),
 			assetId,
 			envId,
 			orgId,
 		)
 	asset, r, err := request.Execute()
 	c.HandleResponse(r)
-	return asset, err
+	return asset, wrapError(r, err)
+}
+
+func (c *client) ListOrgs(ctx context.Context) ([]cac.OrganizationOutput, error) {
+	return Collect(ctx, c.OrgPages(PageOptions{}))
+}
+
+func (c *client) OrgPages(opts PageOptions) *Pager[cac.OrganizationOutput] {
+	links := &pageLinks{}
+	return NewPager(func(ctx context.Context, page Page) ([]cac.OrganizationOutput, bool, error) {
+		request := c.apiClient.OrganizationsApi.OrganizationList(c.withAuth(links.context(ctx)))
+		orgs, r, err := request.Execute()
+		c.HandleResponse(r)
+		return orgs, links.update(r), wrapError(r, err)
+	}, opts)
+}
+
+func (c *client) ListOperations(ctx context.Context, orgId string) ([]cac.OperationOutput, error) {
+	return Collect(ctx, c.OperationPages(orgId, PageOptions{}))
+}
+
+func (c *client) OperationPages(orgId string, opts PageOptions) *Pager[cac.OperationOutput] {
+	links := &pageLinks{}
+	return NewPager(func(ctx context.Context, page Page) ([]cac.OperationOutput, bool, error) {
+		request := c.
+			apiClient.
+			OrganizationsApi.
+			OrganizationGetOperations(c.withAuth(links.context(ctx)), orgId)
+		ops, r, err := request.Execute()
+		c.HandleResponse(r)
+		return ops, links.update(r), wrapError(r, err)
+	}, opts)
+}
+
+func (c *client) ListOperationsByAsset(ctx context.Context, orgId string, assetId string) ([]cac.OperationOutput, error) {
+	return Collect(ctx, c.OperationPagesByAsset(orgId, assetId, PageOptions{}))
+}
+
+func (c *client) OperationPagesByAsset(orgId string, assetId string, opts PageOptions) *Pager[cac.OperationOutput] {
+	links := &pageLinks{}
+	return NewPager(func(ctx context.Context, page Page) ([]cac.OperationOutput, bool, error) {
+		request := c.
+			apiClient.
+			OrganizationsApi.
+			OrganizationGetOperations(c.withAuth(links.context(ctx)), orgId).
+			AssetId(assetId)
+		ops, r, err := request.Execute()
+		c.HandleResponse(r)
+		return ops, links.update(r), wrapError(r, err)
+	}, opts)
+}
+
+func (c *client) DescribeOperation(ctx context.Context, orgId string, opId string) (*cac.OperationOutput, error) {
+	request := c.
+		apiClient.
+		OperationsApi.
+		OperationGet(c.withAuth(ctx), opId, orgId)
+	op, r, err := request.Execute()
+	c.HandleResponse(r)
+	return op, wrapError(r, err)
 }
 
-func (c *client) ListOrgs() ([]cac.OrganizationOutput, error) {
-	request := c.apiClient.OrganizationsApi.OrganizationList(c.ctx)
-	orgs, r, err := request.Execute()
+func (c *client) CancelOperation(ctx context.Context, orgId string, opId string) (*cac.OperationOutput, error) {
+	request := c.
+		apiClient.
+		OperationsApi.
+		OperationCancel(c.withAuth(ctx), opId, orgId)
+	op, r, err := request.Execute()
 	c.HandleResponse(r)
-	return orgs, err
+	return op, wrapError(r, err)
 }
 
-func (c *client) ListOperationsByAsset(orgId string, assetId string) ([]cac.OperationOutput, error) {
+// RetryOperation - starts a new operation that repeats a failed or canceled one
+func (c *client) RetryOperation(ctx context.Context, orgId string, opId string) (*cac.OperationOutput, error) {
 	request := c.
 		apiClient.
-		OrganizationsApi.
-		OrganizationGetOperations(c.ctx, orgId).
-		AssetId(assetId)
-	ops, r, err := request.Execute()
+		OperationsApi.
+		OperationRetry(c.withAuth(ctx), opId, orgId)
+	op, r, err := request.Execute()
 	c.HandleResponse(r)
-	return ops, err
+	return op, wrapError(r, err)
 }
 
-func (c *client) ListAssetBundles(orgId string, envId string) ([]cac.AssetBundle, error) {
+func (c *client) ListAssetBundles(ctx context.Context, orgId string, envId string) ([]cac.AssetBundle, error) {
 	request := c.
 		apiClient.
 		EnvironmentsApi.
 		EnvironmentGetAllowedAssetBundles(
-			c.ctx,
+			c.withAuth(ctx),
 			envId,
 			orgId,
 		)
 	bundles, r, err := request.Execute()
 	c.HandleResponse(r)
-	return bundles, err
+	return bundles, wrapError(r, err)
 }
 
-func (c *client) CreateConnection(orgId, envId, assetId string, params cac.ConnectionInput) (*cac.ConnectionOutput, error) {
+func (c *client) CreateConnection(ctx context.Context, orgId, envId, assetId string, params cac.ConnectionInput) (*cac.ConnectionOutput, error) {
 	request := c.
 		apiClient.
 		ConnectionsApi.
 		ConnectionCreate(
-			c.ctx,
+			c.withAuth(ctx),
 			assetId,
 			envId,
 			orgId,
@@ -225,15 +478,53 @@ func (c *client) CreateConnection(orgId, envId, assetId string, params cac.Conne
 		ConnectionInput(params)
 	conn, r, err := request.Execute()
 	c.HandleResponse(r)
-	return conn, err
+	return conn, wrapError(r, err)
+}
+
+func (c *client) DescribeConnection(ctx context.Context, orgId, envId, assetId, connectionId string) (*cac.ConnectionOutput, error) {
+	request := c.
+		apiClient.
+		ConnectionsApi.
+		ConnectionGet(
+			c.withAuth(ctx),
+			assetId,
+			connectionId,
+			envId,
+			orgId,
+		)
+	conn, r, err := request.Execute()
+	c.HandleResponse(r)
+	return conn, wrapError(r, err)
+}
+
+// ListConnections - connections are embedded in the asset they belong to, so
+// this reads them from the asset or from every asset in the environment
+func (c *client) ListConnections(ctx context.Context, orgId, envId, assetId string) ([]cac.ConnectionOutput, error) {
+	if assetId != "" {
+		asset, err := c.DescribeAsset(ctx, orgId, envId, assetId)
+		if err != nil {
+			return nil, err
+		}
+		return asset.Connections, nil
+	}
+
+	assets, err := c.ListAssets(ctx, orgId, envId)
+	if err != nil {
+		return nil, err
+	}
+	conns := []cac.ConnectionOutput{}
+	for _, asset := range assets {
+		conns = append(conns, asset.Connections...)
+	}
+	return conns, nil
 }
 
-func (c *client) DestroyConnection(orgId, envId, assetId, connectionId string) error {
+func (c *client) DestroyConnection(ctx context.Context, orgId, envId, assetId, connectionId string) error {
 	request := c.
 		apiClient.
 		ConnectionsApi.
 		ConnectionDelete(
-			c.ctx,
+			c.withAuth(ctx),
 			assetId,
 			connectionId,
 			envId,
@@ -241,5 +532,5 @@ func (c *client) DestroyConnection(orgId, envId, assetId, connectionId string) e
 		)
 	_, r, err := request.Execute()
 	c.HandleResponse(r)
-	return err
+	return wrapError(r, err)
 }
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestOperationId(t *testing.T) {
+	const id = "00000000-0000-4000-8000-0000000000aa"
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{name: "operation header", headers: map[string]string{"X-Operation-Id": id}, want: id},
+		{name: "location", headers: map[string]string{"Location": "/api/v1/organizations/org-1/operations/" + id}, want: id},
+		{name: "absolute location", headers: map[string]string{"Location": "https://api.example.com/api/v1/organizations/org-1/operations/" + id + "/"}, want: id},
+		{name: "header wins", headers: map[string]string{"X-Operation-Id": id, "Location": "/api/v1/organizations/org-1/operations/other"}, want: id},
+		{name: "location of the asset", headers: map[string]string{"Location": "/api/v1/organizations/org-1/environments/env-1/assets/asset-1"}, want: ""},
+		{name: "neither", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &http.Response{Header: http.Header{}}
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+			if got := OperationId(r); got != tt.want {
+				t.Errorf("OperationId = %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if got := OperationId(nil); got != "" {
+		t.Errorf("OperationId(nil) = %q", got)
+	}
+}
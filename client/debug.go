@@ -0,0 +1,368 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redacted - replaces any secret the debug output or recordings would contain
+const Redacted = "[REDACTED]"
+
+var (
+	secretHeaders = map[string]bool{
+		"Authorization":       true,
+		"Proxy-Authorization": true,
+		"Cookie":              true,
+		"Set-Cookie":          true,
+		"X-Api-Key":           true,
+	}
+	secretField = regexp.MustCompile(`(?i)(token|secret|password|passphrase|api[_-]?key|private[_-]?key|credential|otp)`)
+)
+
+// RedactHeaders - copy of the headers with credentials removed
+func RedactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	for name := range redacted {
+		if secretHeaders[http.CanonicalHeaderKey(name)] {
+			redacted[name] = []string{Redacted}
+		}
+	}
+	return redacted
+}
+
+// RedactURL - copy of the url with secret-looking query parameters removed
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	redacted := *u
+	redacted.RawQuery = redactQuery(u.Query()).Encode()
+	return redacted.String()
+}
+
+func redactQuery(query url.Values) url.Values {
+	for key := range query {
+		if secretField.MatchString(key) {
+			query[key] = []string{Redacted}
+		}
+	}
+	return query
+}
+
+// RedactBody - replaces the values of secret-looking json fields, bodies that
+// are not json are returned untouched
+func RedactBody(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return body
+	}
+
+	redacted, err := json.Marshal(redactValue(parsed))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch data := value.(type) {
+	case map[string]interface{}:
+		for key, val := range data {
+			if secretField.MatchString(key) {
+				data[key] = Redacted
+				continue
+			}
+			data[key] = redactValue(val)
+		}
+		return data
+	case []interface{}:
+		for i, val := range data {
+			data[i] = redactValue(val)
+		}
+		return data
+	default:
+		return data
+	}
+}
+
+// readBody - returns the body and leaves an unread copy in place
+func readBody(body *io.ReadCloser) []byte {
+	if *body == nil || *body == http.NoBody {
+		return nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func requestBody(req *http.Request) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err == nil {
+			defer body.Close()
+			data, _ := io.ReadAll(body)
+			return data
+		}
+	}
+	return readBody(&req.Body)
+}
+
+// debugTransport - http.RoundTripper that logs redacted requests and
+// responses with their timing and optionally records them to a HAR file
+type debugTransport struct {
+	base    http.RoundTripper
+	out     io.Writer
+	harPath string
+
+	mu  sync.Mutex
+	har harLog
+}
+
+func newDebugTransport(base http.RoundTripper, out io.Writer, harPath string) *debugTransport {
+	return &debugTransport{
+		base:    base,
+		out:     out,
+		harPath: harPath,
+		har:     newHarLog(),
+	}
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody := requestBody(req)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	var respBody []byte
+	if resp != nil {
+		respBody = readBody(&resp.Body)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.out != nil {
+		t.print(req, reqBody, resp, respBody, err, elapsed)
+	}
+	if t.harPath != "" {
+		t.har.Log.Entries = append(t.har.Log.Entries, newHarEntry(req, reqBody, resp, respBody, start, elapsed))
+		if werr := t.har.write(t.harPath); werr != nil && t.out != nil {
+			fmt.Fprintf(t.out, "unable to write HAR file %s: %s\n", t.harPath, werr)
+		}
+	}
+
+	return resp, err
+}
+
+func writeHeaders(w io.Writer, headers http.Header) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s: %s\n", name, strings.Join(headers[name], ", "))
+	}
+}
+
+func (t *debugTransport) print(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, err error, elapsed time.Duration) {
+	fmt.Fprintf(t.out, "--- DEBUG %s ---\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(t.out, "REQUEST:\n%s %s\n", req.Method, RedactURL(req.URL))
+	writeHeaders(t.out, RedactHeaders(req.Header))
+	if len(reqBody) > 0 {
+		fmt.Fprintf(t.out, "\n%s\n", RedactBody(reqBody))
+	}
+
+	if err != nil {
+		fmt.Fprintf(t.out, "REQUEST FAILED after %s: %s\n\n", elapsed, err)
+		return
+	}
+
+	fmt.Fprintf(t.out, "RESPONSE (%s):\n%s\n", elapsed, resp.Status)
+	writeHeaders(t.out, RedactHeaders(resp.Header))
+	if len(respBody) > 0 {
+		fmt.Fprintf(t.out, "\n%s\n", RedactBody(respBody))
+	}
+	fmt.Fprintln(t.out)
+}
+
+// harLog - minimal HAR 1.2 document, see http://www.softwareishard.com/blog/har-12-spec/
+type harLog struct {
+	Log struct {
+		Version string `json:"version"`
+		Creator struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harEntry struct {
+	StartedDateTime string                 `json:"startedDateTime"`
+	Time            float64                `json:"time"`
+	Request         harRequest             `json:"request"`
+	Response        harResponse            `json:"response"`
+	Cache           map[string]interface{} `json:"cache"`
+	Timings         harTimings             `json:"timings"`
+	Comment         string                 `json:"comment,omitempty"`
+}
+
+func newHarLog() harLog {
+	har := harLog{}
+	har.Log.Version = "1.2"
+	har.Log.Creator.Name = "aptible"
+	har.Log.Creator.Version = "cloud-cli"
+	har.Log.Entries = []harEntry{}
+	return har
+}
+
+func harHeaders(headers http.Header) []harNameValue {
+	pairs := []harNameValue{}
+	for name, values := range RedactHeaders(headers) {
+		for _, value := range values {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+	return pairs
+}
+
+func harQuery(u *url.URL) []harNameValue {
+	pairs := []harNameValue{}
+	for key, values := range redactQuery(u.Query()) {
+		for _, value := range values {
+			pairs = append(pairs, harNameValue{Name: key, Value: value})
+		}
+	}
+	return pairs
+}
+
+func newHarEntry(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, start time.Time, elapsed time.Duration) harEntry {
+	ms := float64(elapsed) / float64(time.Millisecond)
+	entry := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            ms,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         RedactURL(req.URL),
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(req.Header),
+			QueryString: harQuery(req.URL),
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Cache:   map[string]interface{}{},
+		Timings: harTimings{Send: 0, Wait: ms, Receive: 0},
+	}
+	if len(reqBody) > 0 {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     string(RedactBody(reqBody)),
+		}
+	}
+
+	if resp == nil {
+		entry.Response = harResponse{
+			Headers:     []harNameValue{},
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		}
+		entry.Comment = "request failed before a response was received"
+		return entry
+	}
+
+	entry.Response = harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: resp.Proto,
+		Headers:     harHeaders(resp.Header),
+		Cookies:     []harNameValue{},
+		Content: harContent{
+			Size:     len(respBody),
+			MimeType: resp.Header.Get("Content-Type"),
+			Text:     string(RedactBody(respBody)),
+		},
+		HeadersSize: -1,
+		BodySize:    len(respBody),
+	}
+	return entry
+}
+
+// write - rewrites the whole file so it is valid even if the cli exits early
+func (h harLog) write(path string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "empty", body: "", want: ""},
+		{name: "not json", body: "token=secret", want: "token=secret"},
+		{name: "secret fields", body: `{"access_token":"secret","name":"db"}`, want: `{"access_token":"[REDACTED]","name":"db"}`},
+		{name: "nested", body: `{"data":[{"password":"secret","otp":"123456"}]}`, want: `{"data":[{"otp":"[REDACTED]","password":"[REDACTED]"}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(RedactBody([]byte(tt.body))); got != tt.want {
+				t.Errorf("RedactBody = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDebugTransportRedacts(t *testing.T) {
+	const token = "eyJhbGciOiJIUzI1NiJ9.secret-claims.secret-signature"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session="+token)
+		w.Write([]byte(`{"access_token":"` + token + `","name":"demo"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	harPath := path.Join(t.TempDir(), "requests.har")
+	transport := newDebugTransport(http.DefaultTransport, &out, harPath)
+
+	req, err := http.NewRequest("POST", server.URL+"/tokens?api_key="+token+"&page=2", strings.NewReader(`{"email":"ops@example.com","password":"`+token+`"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Proxy-Authorization", "Basic "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	har, err := os.ReadFile(harPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed harLog
+	if err := json.Unmarshal(har, &parsed); err != nil || len(parsed.Log.Entries) != 1 {
+		t.Fatalf("invalid HAR file (%v):\n%s", err, har)
+	}
+
+	for name, text := range map[string]string{"debug output": out.String(), "HAR file": string(har)} {
+		for _, secret := range []string{token, "secret-claims", "Bearer e"} {
+			if strings.Contains(text, secret) {
+				t.Errorf("%s contains %q:\n%s", name, secret, text)
+			}
+		}
+		// what is not secret is still there to debug with
+		for _, kept := range []string{Redacted, "ops@example.com", "demo", "page"} {
+			if !strings.Contains(text, kept) {
+				t.Errorf("%s is missing %q:\n%s", name, kept, text)
+			}
+		}
+	}
+}
+
+func TestDebugTransportFailedRequest(t *testing.T) {
+	const token = "secret-token"
+	var out bytes.Buffer
+	harPath := path.Join(t.TempDir(), "requests.har")
+	transport := newDebugTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, http.ErrHandlerTimeout
+	}), &out, harPath)
+
+	req, err := http.NewRequest("GET", "https://api.example.com/organizations", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if _, err := transport.RoundTrip(req); err == nil {
+		t.Fatal("the failure was swallowed")
+	}
+
+	har, err := os.ReadFile(harPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "REQUEST FAILED") {
+		t.Errorf("debug output does not report the failure:\n%s", out.String())
+	}
+	for name, text := range map[string]string{"debug output": out.String(), "HAR file": string(har)} {
+		if strings.Contains(text, token) {
+			t.Errorf("%s contains the token:\n%s", name, text)
+		}
+	}
+}
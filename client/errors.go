@@ -0,0 +1,298 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+)
+
+// ErrorKind - broad category of a cloud api failure, each maps to an exit code
+type ErrorKind int
+
+const (
+	KindUnknown ErrorKind = iota
+	KindNotFound
+	KindUnauthorized
+	KindForbidden
+	KindValidation
+	KindConflict
+	KindRateLimited
+	KindServerError
+)
+
+// Exit codes returned by the cli, these are part of the public interface and
+// must not be renumbered.
+const (
+	ExitOK           = 0
+	ExitError        = 1
+	ExitNotFound     = 3
+	ExitUnauthorized = 4
+	ExitForbidden    = 5
+	ExitValidation   = 6
+	ExitConflict     = 7
+	ExitRateLimited  = 8
+	ExitServerError  = 9
+	ExitTimeout      = 124
+	ExitInterrupted  = 130
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not found"
+	case KindUnauthorized:
+		return "unauthorized"
+	case KindForbidden:
+		return "forbidden"
+	case KindValidation:
+		return "validation failed"
+	case KindConflict:
+		return "conflict"
+	case KindRateLimited:
+		return "rate limited"
+	case KindServerError:
+		return "server error"
+	default:
+		return "request failed"
+	}
+}
+
+// ExitCode - the process exit code for this kind of failure
+func (k ErrorKind) ExitCode() int {
+	switch k {
+	case KindNotFound:
+		return ExitNotFound
+	case KindUnauthorized:
+		return ExitUnauthorized
+	case KindForbidden:
+		return ExitForbidden
+	case KindValidation:
+		return ExitValidation
+	case KindConflict:
+		return ExitConflict
+	case KindRateLimited:
+		return ExitRateLimited
+	case KindServerError:
+		return ExitServerError
+	default:
+		return ExitError
+	}
+}
+
+func kindFromStatus(code int) ErrorKind {
+	switch {
+	case code == http.StatusNotFound:
+		return KindNotFound
+	case code == http.StatusUnauthorized:
+		return KindUnauthorized
+	case code == http.StatusForbidden:
+		return KindForbidden
+	case code == http.StatusBadRequest, code == http.StatusUnprocessableEntity:
+		return KindValidation
+	case code == http.StatusConflict:
+		return KindConflict
+	case code == http.StatusTooManyRequests:
+		return KindRateLimited
+	case code >= 500:
+		return KindServerError
+	default:
+		return KindUnknown
+	}
+}
+
+// Sentinels for errors.Is, e.g. errors.Is(err, client.ErrNotFound)
+var (
+	ErrNotFound     = &APIError{Kind: KindNotFound}
+	ErrUnauthorized = &APIError{Kind: KindUnauthorized}
+	ErrForbidden    = &APIError{Kind: KindForbidden}
+	ErrValidation   = &APIError{Kind: KindValidation}
+	ErrConflict     = &APIError{Kind: KindConflict}
+	ErrRateLimited  = &APIError{Kind: KindRateLimited}
+	ErrServerError  = &APIError{Kind: KindServerError}
+)
+
+// APIError - a decoded error response from the cloud api
+type APIError struct {
+	Kind       ErrorKind
+	StatusCode int
+	Message    string
+	// Fields - validation messages keyed by the offending field
+	Fields    map[string]string
+	RequestID string
+	// Err - the original error returned by the generated client
+	Err error
+}
+
+func (e *APIError) Error() string {
+	msg := e.Kind.String()
+	if e.Message != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Message)
+	}
+	if e.StatusCode != 0 {
+		msg = fmt.Sprintf("%s (%d)", msg, e.StatusCode)
+	}
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is - matches any APIError of the same kind so sentinels work with errors.Is
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+	return t.Kind == e.Kind
+}
+
+// Render - human readable, multi-line version of the error
+func (e *APIError) Render() string {
+	s := fmt.Sprintf("Error: %s", e.Error())
+
+	keys := make([]string, 0, len(e.Fields))
+	for key := range e.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		s += fmt.Sprintf("\n  %s: %s", key, e.Fields[key])
+	}
+
+	if e.RequestID != "" {
+		s += fmt.Sprintf("\nRequest ID: %s", e.RequestID)
+	}
+	return s
+}
+
+// errorBody - the error shapes the cloud api responds with
+type errorBody struct {
+	Detail  json.RawMessage `json:"detail"`
+	Message string          `json:"message"`
+	Error   string          `json:"error"`
+}
+
+type validationDetail struct {
+	Loc []interface{} `json:"loc"`
+	Msg string        `json:"msg"`
+}
+
+func decodeErrorBody(body []byte, apiErr *APIError) {
+	var parsed errorBody
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+		return
+	}
+
+	switch {
+	case parsed.Message != "":
+		apiErr.Message = parsed.Message
+	case parsed.Error != "":
+		apiErr.Message = parsed.Error
+	}
+
+	if len(parsed.Detail) == 0 {
+		return
+	}
+
+	var detail string
+	if err := json.Unmarshal(parsed.Detail, &detail); err == nil {
+		apiErr.Message = detail
+		return
+	}
+
+	var details []validationDetail
+	if err := json.Unmarshal(parsed.Detail, &details); err == nil {
+		apiErr.Fields = map[string]string{}
+		for _, d := range details {
+			apiErr.Fields[fieldName(d.Loc)] = d.Msg
+		}
+	}
+}
+
+// fieldName - turns ["body", "asset_parameters", "name"] into asset_parameters.name
+func fieldName(loc []interface{}) string {
+	parts := []string{}
+	for i, part := range loc {
+		if i == 0 && part == "body" {
+			continue
+		}
+		parts = append(parts, fmt.Sprint(part))
+	}
+	if len(parts) == 0 {
+		return "request"
+	}
+	return strings.Join(parts, ".")
+}
+
+func requestID(r *http.Response) string {
+	for _, header := range []string{"X-Request-Id", "X-Amzn-Requestid", "X-Amz-Cf-Id"} {
+		if id := r.Header.Get(header); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// wrapError - converts errors from the generated client into an *APIError
+// when the api responded, anything else is returned untouched
+func wrapError(r *http.Response, err error) error {
+	if err == nil || r == nil || r.StatusCode < 400 {
+		return err
+	}
+
+	apiErr := &APIError{
+		Kind:       kindFromStatus(r.StatusCode),
+		StatusCode: r.StatusCode,
+		RequestID:  requestID(r),
+		Err:        err,
+	}
+
+	var genericErr *cac.GenericOpenAPIError
+	if errors.As(err, &genericErr) {
+		decodeErrorBody(genericErr.Body(), apiErr)
+	}
+
+	return apiErr
+}
+
+// ExitCode - the process exit code for any error returned by a command
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Kind.ExitCode()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitTimeout
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitInterrupted
+	}
+	return ExitError
+}
+
+// RenderError - human readable version of any error returned by a command
+func RenderError(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Render()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "Error: timed out waiting for the cloud api, see --timeout"
+	}
+	if errors.Is(err, context.Canceled) {
+		return "Error: interrupted"
+	}
+	return fmt.Sprintf("Error: %s", err)
+}
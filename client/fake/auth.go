@@ -0,0 +1,110 @@
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultTokenLifetime - used when a token request does not ask for one
+const DefaultTokenLifetime = 12 * time.Hour
+
+// AuthServer - stand-in for the token endpoint of the auth server, it knows a
+// single account and issues unsigned JWTs for it
+type AuthServer struct {
+	Email    string
+	Password string
+	// OTP - when set the account has two-factor auth and this code is required
+	OTP string
+	// Log - when set every request is written to it
+	Log io.Writer
+	Now func() time.Time
+}
+
+var _ http.Handler = (*AuthServer)(nil)
+
+type tokenRequest struct {
+	GrantType string `json:"grant_type"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	ExpiresIn int    `json:"expires_in"`
+	OTPToken  string `json:"otp_token"`
+}
+
+func (a *AuthServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	status := a.serve(w, r)
+	if a.Log != nil {
+		fmt.Fprintf(a.Log, "%s %s %s -> %d (%s)\n", start.Format(time.RFC3339), r.Method, r.URL.RequestURI(), status, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+func (a *AuthServer) serve(w http.ResponseWriter, r *http.Request) int {
+	if r.URL.Path != "/tokens" {
+		return writeAuthError(w, http.StatusNotFound, "not_found", "Not Found")
+	}
+	if r.Method != http.MethodPost {
+		return writeAuthError(w, http.StatusMethodNotAllowed, "method_not_allowed", "Method Not Allowed")
+	}
+
+	var req tokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return writeAuthError(w, http.StatusBadRequest, "invalid_request", fmt.Sprintf("invalid request body: %s", err))
+	}
+	if req.GrantType != "password" {
+		return writeAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "only the password grant is supported")
+	}
+	if req.Username != a.Email || req.Password != a.Password {
+		return writeAuthError(w, http.StatusUnauthorized, "invalid_credentials", "Invalid email or password")
+	}
+	if a.OTP != "" {
+		if req.OTPToken == "" {
+			return writeAuthError(w, http.StatusUnauthorized, "otp_token_required", "An OTP token is required")
+		}
+		if req.OTPToken != a.OTP {
+			return writeAuthError(w, http.StatusUnauthorized, "invalid_otp_token", "Invalid OTP token")
+		}
+	}
+
+	lifetime := DefaultTokenLifetime
+	if req.ExpiresIn > 0 {
+		lifetime = time.Duration(req.ExpiresIn) * time.Second
+	}
+	return writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"access_token": a.token(r, lifetime),
+		"token_type":   "bearer",
+		"expires_in":   int(lifetime.Seconds()),
+	})
+}
+
+// token - a JWT with the usual claims and no signature, the mock api accepts
+// any token so nothing ever verifies it
+func (a *AuthServer) token(r *http.Request, lifetime time.Duration) string {
+	now := time.Now()
+	if a.Now != nil {
+		now = a.Now()
+	}
+	header, _ := json.Marshal(map[string]string{"alg": "none", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   fmt.Sprintf("http://%s", r.Host),
+		"sub":   a.Email,
+		"email": a.Email,
+		"scope": "manage",
+		"iat":   now.Unix(),
+		"exp":   now.Add(lifetime).Unix(),
+	})
+	enc := base64.RawURLEncoding
+	return fmt.Sprintf("%s.%s.", enc.EncodeToString(header), enc.EncodeToString(claims))
+}
+
+// writeAuthError - same shape as the auth server's own errors
+func writeAuthError(w http.ResponseWriter, status int, code string, message string) int {
+	return writeJSON(w, status, map[string]interface{}{
+		"code":    status,
+		"error":   code,
+		"message": message,
+	})
+}
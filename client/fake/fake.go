@@ -0,0 +1,833 @@
+/*
+Package fake
+An in-memory, concurrency-safe implementation of client.CloudClient.  It is
+used by the hidden --offline flag and lets commands run without the cloud api.
+Server exposes the same store over http for `aptible dev mock-server`.
+Assets and operations move through their statuses as time passes, one status
+every Step, the same way the real api reports asynchronous provisioning.
+*/
+package fake
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// ids of the records created by NewSeeded
+const (
+	DemoOrgId = "00000000-0000-4000-8000-000000000001"
+	DemoEnvId = "00000000-0000-4000-8000-000000000002"
+	DemoVpcId = "00000000-0000-4000-8000-000000000003"
+)
+
+type assetRecord struct {
+	orgId string
+	envId string
+	// seq - creation order, used to keep listings stable
+	seq   int
+	asset cac.AssetOutput
+	// since - when the current lifecycle (deploy or destroy) started
+	since      time.Time
+	destroying bool
+}
+
+type opRecord struct {
+	orgId   string
+	assetId string
+	op      cac.OperationOutput
+	since   time.Time
+	// failure - when set the operation ends up FAILED with this message
+	failure    string
+	canceledAt time.Time
+}
+
+type connRecord struct {
+	seq           int
+	orgId         string
+	envId         string
+	incomingAsset string
+	outgoingAsset string
+	conn          cac.ConnectionOutput
+}
+
+// Client - in-memory client.CloudClient
+type Client struct {
+	// Step - how long an asset or operation stays in each intermediate status
+	Step time.Duration
+	// Latency - simulated delay for every call
+	Latency time.Duration
+	// Now - clock used for status transitions
+	Now func() time.Time
+
+	mu      sync.Mutex
+	orgs    map[string]cac.OrganizationOutput
+	envs    map[string]cac.EnvironmentOutput
+	envOrg  map[string]string
+	assets  map[string]*assetRecord
+	ops     []*opRecord
+	conns   map[string]*connRecord
+	bundles []cac.AssetBundle
+	seq     int
+}
+
+var _ client.CloudClient = (*Client)(nil)
+
+// New - an empty fake, use the Create* methods to populate it
+func New() *Client {
+	return &Client{
+		Step:   5 * time.Second,
+		Now:    time.Now,
+		orgs:   map[string]cac.OrganizationOutput{},
+		envs:   map[string]cac.EnvironmentOutput{},
+		envOrg: map[string]string{},
+		assets: map[string]*assetRecord{},
+		conns:  map[string]*connRecord{},
+		bundles: []cac.AssetBundle{
+			{Identifier: "aws/rds", Name: "RDS", Description: "Managed relational database (postgres, mysql)"},
+			{Identifier: "aws/vpc", Name: "VPC", Description: "Private network for your assets"},
+		},
+	}
+}
+
+// NewSeeded - a fake with a demo organization, environment and deployed vpc
+func NewSeeded() *Client {
+	f := New()
+	awsOu := "ou-demo-00000000"
+	awsAccount := "000000000000"
+	desc := "demo environment"
+	now := f.Now()
+
+	f.orgs[DemoOrgId] = cac.OrganizationOutput{
+		Id:    DemoOrgId,
+		Name:  "Demo Organization",
+		AwsOu: &awsOu,
+	}
+	f.envs[DemoEnvId] = cac.EnvironmentOutput{
+		Id:           DemoEnvId,
+		Name:         "demo",
+		Description:  &desc,
+		Data:         map[string]interface{}{},
+		AwsAccountId: &awsAccount,
+	}
+	f.envOrg[DemoEnvId] = DemoOrgId
+	vpc := &assetRecord{
+		orgId: DemoOrgId,
+		envId: DemoEnvId,
+		// far enough in the past that the vpc is already deployed
+		since: now.Add(-time.Hour),
+		asset: cac.AssetOutput{
+			Id:           DemoVpcId,
+			Asset:        "aws__vpc__latest",
+			AssetVersion: "latest",
+		},
+	}
+	vpc.asset.CurrentAssetParameters.Data = map[string]interface{}{"name": "demo-vpc"}
+	f.assets[DemoVpcId] = vpc
+	failed := f.newOp(DemoOrgId, DemoVpcId, cac.OPERATIONTYPE_APPLY, now.Add(-2*time.Hour))
+	failed.failure = "timed out waiting for the vpc to become available"
+	f.ops = append(f.ops, failed)
+	f.ops = append(f.ops, f.newOp(DemoOrgId, DemoVpcId, cac.OPERATIONTYPE_APPLY, now.Add(-time.Hour)))
+
+	return f
+}
+
+func newId() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func notFound(kind, id string) error {
+	return &client.APIError{
+		Kind:       client.KindNotFound,
+		StatusCode: 404,
+		Message:    fmt.Sprintf("%s %s does not exist", kind, id),
+	}
+}
+
+// wait - simulated latency which still honors cancellation
+func (f *Client) wait(ctx context.Context) error {
+	if f.Latency <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(f.Latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// steps - how many statuses a lifecycle that started at since has advanced
+func (f *Client) steps(since time.Time) int {
+	if f.Step <= 0 {
+		return 2
+	}
+	return int(f.Now().Sub(since) / f.Step)
+}
+
+func (f *Client) assetStatus(rec *assetRecord) cac.AssetStatus {
+	steps := f.steps(rec.since)
+	if rec.destroying {
+		if steps < 1 {
+			return cac.ASSETSTATUS_DESTROYING
+		}
+		return cac.ASSETSTATUS_DESTROYED
+	}
+
+	switch {
+	case steps < 1:
+		return cac.ASSETSTATUS_REQUESTED
+	case steps < 2:
+		return cac.ASSETSTATUS_DEPLOYING
+	default:
+		return cac.ASSETSTATUS_DEPLOYED
+	}
+}
+
+func (f *Client) opStatus(rec *opRecord) cac.OperationStatus {
+	if !rec.canceledAt.IsZero() {
+		return cac.OPERATIONSTATUS_CANCELED
+	}
+
+	switch steps := f.steps(rec.since); {
+	case steps < 1:
+		return cac.OPERATIONSTATUS_PENDING
+	case steps < 2:
+		return cac.OPERATIONSTATUS_IN_PROGRESS
+	case rec.failure != "":
+		return cac.OPERATIONSTATUS_FAILED
+	default:
+		return cac.OPERATIONSTATUS_COMPLETE
+	}
+}
+
+// assetView - copy of the asset with its current status
+func (f *Client) assetView(rec *assetRecord) cac.AssetOutput {
+	asset := rec.asset
+	asset.Status = f.assetStatus(rec)
+	asset.Connections = []cac.ConnectionOutput{}
+	for _, conn := range f.sortedConns() {
+		if conn.incomingAsset == asset.Id {
+			asset.Connections = append(asset.Connections, f.connView(conn))
+		}
+	}
+	return asset
+}
+
+func (f *Client) opView(rec *opRecord) cac.OperationOutput {
+	op := rec.op
+	status := f.opStatus(rec)
+	op.Status = *cac.NewNullableOperationStatus(&status)
+
+	// updated whenever the operation last changed status
+	switch steps := f.steps(rec.since); {
+	case !rec.canceledAt.IsZero():
+		op.UpdatedAt = rec.canceledAt
+	case steps > 2:
+		op.UpdatedAt = rec.since.Add(2 * f.Step)
+	default:
+		op.UpdatedAt = rec.since.Add(time.Duration(steps) * f.Step)
+	}
+	if status == cac.OPERATIONSTATUS_FAILED {
+		msg := rec.failure
+		op.ErrorMessage = &msg
+	}
+	return op
+}
+
+func (f *Client) op(orgId, opId string) (*opRecord, error) {
+	for _, rec := range f.ops {
+		if rec.op.Id == opId && rec.orgId == orgId {
+			return rec, nil
+		}
+	}
+	return nil, notFound("operation", opId)
+}
+
+func conflict(format string, args ...interface{}) error {
+	return &client.APIError{
+		Kind:       client.KindConflict,
+		StatusCode: 409,
+		Message:    fmt.Sprintf(format, args...),
+	}
+}
+
+// connView - connection with a shallow copy of both assets
+func (f *Client) connView(rec *connRecord) cac.ConnectionOutput {
+	conn := rec.conn
+	if in, ok := f.assets[rec.incomingAsset]; ok {
+		asset := in.asset
+		asset.Status = f.assetStatus(in)
+		conn.IncomingConnectionAsset = &asset
+	}
+	if out, ok := f.assets[rec.outgoingAsset]; ok {
+		asset := out.asset
+		asset.Status = f.assetStatus(out)
+		conn.OutgoingConnectionAsset = &asset
+	}
+	return conn
+}
+
+func (f *Client) sortedConns() []*connRecord {
+	conns := make([]*connRecord, 0, len(f.conns))
+	for _, conn := range f.conns {
+		conns = append(conns, conn)
+	}
+	sort.Slice(conns, func(i, j int) bool { return conns[i].seq < conns[j].seq })
+	return conns
+}
+
+func (f *Client) newOp(orgId, assetId string, opType cac.OperationType, since time.Time) *opRecord {
+	status := cac.OPERATIONSTATUS_PENDING
+	return &opRecord{
+		orgId:   orgId,
+		assetId: assetId,
+		since:   since,
+		op: cac.OperationOutput{
+			Id:             newId(),
+			OperationType:  *cac.NewNullableOperationType(&opType),
+			Status:         *cac.NewNullableOperationStatus(&status),
+			AssetId:        assetId,
+			OrganizationId: orgId,
+			CreatedAt:      since,
+			UpdatedAt:      since,
+		},
+	}
+}
+
+func (f *Client) env(orgId, envId string) (cac.EnvironmentOutput, error) {
+	env, ok := f.envs[envId]
+	if !ok || f.envOrg[envId] != orgId {
+		return env, notFound("environment", envId)
+	}
+	return env, nil
+}
+
+func (f *Client) asset(orgId, envId, assetId string) (*assetRecord, error) {
+	rec, ok := f.assets[assetId]
+	if !ok || rec.orgId != orgId || rec.envId != envId {
+		return nil, notFound("asset", assetId)
+	}
+	return rec, nil
+}
+
+func (f *Client) ListEnvironments(ctx context.Context, orgId string) ([]cac.EnvironmentOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.orgs[orgId]; !ok {
+		return nil, notFound("organization", orgId)
+	}
+	envs := []cac.EnvironmentOutput{}
+	for id, env := range f.envs {
+		if f.envOrg[id] == orgId {
+			envs = append(envs, env)
+		}
+	}
+	sort.Slice(envs, func(i, j int) bool { return envs[i].Name < envs[j].Name })
+	return envs, nil
+}
+
+func (f *Client) EnvironmentPages(orgId string, opts client.PageOptions) *client.Pager[cac.EnvironmentOutput] {
+	return pages(func(ctx context.Context) ([]cac.EnvironmentOutput, error) {
+		return f.ListEnvironments(ctx, orgId)
+	}, opts)
+}
+
+func (f *Client) CreateEnvironment(ctx context.Context, orgId string, params cac.EnvironmentInput) (*cac.EnvironmentOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.orgs[orgId]; !ok {
+		return nil, notFound("organization", orgId)
+	}
+	env := cac.EnvironmentOutput{
+		Id:          newId(),
+		Name:        params.Name,
+		Description: params.Description,
+		Data:        params.Data,
+	}
+	f.envs[env.Id] = env
+	f.envOrg[env.Id] = orgId
+	return &env, nil
+}
+
+func (f *Client) DescribeEnvironment(ctx context.Context, orgId, envId string) (*cac.EnvironmentOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	env, err := f.env(orgId, envId)
+	if err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
+func (f *Client) UpdateEnvironment(ctx context.Context, orgId, envId string, params cac.EnvironmentInput) (*cac.EnvironmentOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	env, err := f.env(orgId, envId)
+	if err != nil {
+		return nil, err
+	}
+	env.Name = params.Name
+	env.Description = params.Description
+	env.Data = params.Data
+	f.envs[envId] = env
+	return &env, nil
+}
+
+func (f *Client) DestroyEnvironment(ctx context.Context, orgId, envId string) error {
+	if err := f.wait(ctx); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.env(orgId, envId); err != nil {
+		return err
+	}
+	delete(f.envs, envId)
+	delete(f.envOrg, envId)
+	return nil
+}
+
+func (f *Client) ListOrgs(ctx context.Context) ([]cac.OrganizationOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	orgs := []cac.OrganizationOutput{}
+	for _, org := range f.orgs {
+		orgs = append(orgs, org)
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].Name < orgs[j].Name })
+	return orgs, nil
+}
+
+func (f *Client) OrgPages(opts client.PageOptions) *client.Pager[cac.OrganizationOutput] {
+	return pages(f.ListOrgs, opts)
+}
+
+func orgFromInput(orgId string, params cac.OrganizationInput) cac.OrganizationOutput {
+	return cac.OrganizationOutput{
+		Id:             orgId,
+		Name:           params.Name,
+		BaaStatus:      params.BaaStatus,
+		AwsOu:          params.AwsOu,
+		ContactDetails: params.ContactDetails,
+	}
+}
+
+func (f *Client) CreateOrg(ctx context.Context, params cac.OrganizationInput) (*cac.OrganizationOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	org := orgFromInput(newId(), params)
+	f.orgs[org.Id] = org
+	return &org, nil
+}
+
+func (f *Client) UpdateOrg(ctx context.Context, orgId string, params cac.OrganizationInput) (*cac.OrganizationOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.orgs[orgId]; !ok {
+		return nil, notFound("organization", orgId)
+	}
+	org := orgFromInput(orgId, params)
+	f.orgs[orgId] = org
+	return &org, nil
+}
+
+func (f *Client) FindOrg(ctx context.Context, orgId string) (*cac.OrganizationOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	org, ok := f.orgs[orgId]
+	if !ok {
+		return nil, notFound("organization", orgId)
+	}
+	return &org, nil
+}
+
+func (f *Client) ListAssetBundles(ctx context.Context, orgId, envId string) ([]cac.AssetBundle, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.env(orgId, envId); err != nil {
+		return nil, err
+	}
+	return append([]cac.AssetBundle{}, f.bundles...), nil
+}
+
+func (f *Client) CreateAsset(ctx context.Context, orgId, envId string, params cac.AssetInput) (*cac.AssetOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.env(orgId, envId); err != nil {
+		return nil, err
+	}
+
+	now := f.Now()
+	f.seq++
+	rec := &assetRecord{
+		orgId: orgId,
+		envId: envId,
+		seq:   f.seq,
+		since: now,
+		asset: cac.AssetOutput{
+			Id:           newId(),
+			Asset:        params.Asset,
+			AssetVersion: params.AssetVersion,
+		},
+	}
+	rec.asset.CurrentAssetParameters.Data = params.AssetParameters
+	f.assets[rec.asset.Id] = rec
+	f.ops = append(f.ops, f.newOp(orgId, rec.asset.Id, cac.OPERATIONTYPE_APPLY, now))
+
+	asset := f.assetView(rec)
+	return &asset, nil
+}
+
+func (f *Client) ListAssets(ctx context.Context, orgId, envId string) ([]cac.AssetOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.env(orgId, envId); err != nil {
+		return nil, err
+	}
+	recs := []*assetRecord{}
+	for _, rec := range f.assets {
+		if rec.orgId == orgId && rec.envId == envId {
+			recs = append(recs, rec)
+		}
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].seq < recs[j].seq })
+
+	assets := []cac.AssetOutput{}
+	for _, rec := range recs {
+		assets = append(assets, f.assetView(rec))
+	}
+	return assets, nil
+}
+
+func (f *Client) AssetPages(orgId, envId string, opts client.PageOptions) *client.Pager[cac.AssetOutput] {
+	return pages(func(ctx context.Context) ([]cac.AssetOutput, error) {
+		return f.ListAssets(ctx, orgId, envId)
+	}, opts)
+}
+
+func (f *Client) DescribeAsset(ctx context.Context, orgId, envId, assetId string) (*cac.AssetOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, err := f.asset(orgId, envId, assetId)
+	if err != nil {
+		return nil, err
+	}
+	asset := f.assetView(rec)
+	return &asset, nil
+}
+
+// UpdateAsset - replaces the parameters and redeploys the asset
+func (f *Client) UpdateAsset(ctx context.Context, orgId, envId, assetId string, params cac.AssetInput) (*cac.AssetOutput, string, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, err := f.asset(orgId, envId, assetId)
+	if err != nil {
+		return nil, "", err
+	}
+	now := f.Now()
+	if params.AssetVersion != "" {
+		rec.asset.AssetVersion = params.AssetVersion
+	}
+	rec.asset.CurrentAssetParameters.Data = params.AssetParameters
+	rec.since = now
+	op := f.newOp(orgId, assetId, cac.OPERATIONTYPE_APPLY, now)
+	f.ops = append(f.ops, op)
+
+	asset := f.assetView(rec)
+	return &asset, op.op.Id, nil
+}
+
+func (f *Client) DestroyAsset(ctx context.Context, orgId, envId, assetId string) error {
+	if err := f.wait(ctx); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, err := f.asset(orgId, envId, assetId)
+	if err != nil {
+		return err
+	}
+	now := f.Now()
+	rec.destroying = true
+	rec.since = now
+	f.ops = append(f.ops, f.newOp(orgId, assetId, cac.OPERATIONTYPE_DESTROY, now))
+	return nil
+}
+
+func (f *Client) ListOperationsByAsset(ctx context.Context, orgId, assetId string) ([]cac.OperationOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ops := []cac.OperationOutput{}
+	for _, rec := range f.ops {
+		if rec.orgId == orgId && rec.assetId == assetId {
+			ops = append(ops, f.opView(rec))
+		}
+	}
+	return ops, nil
+}
+
+func (f *Client) OperationPagesByAsset(orgId, assetId string, opts client.PageOptions) *client.Pager[cac.OperationOutput] {
+	return pages(func(ctx context.Context) ([]cac.OperationOutput, error) {
+		return f.ListOperationsByAsset(ctx, orgId, assetId)
+	}, opts)
+}
+
+func (f *Client) ListOperations(ctx context.Context, orgId string) ([]cac.OperationOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.orgs[orgId]; !ok {
+		return nil, notFound("organization", orgId)
+	}
+	ops := []cac.OperationOutput{}
+	for _, rec := range f.ops {
+		if rec.orgId == orgId {
+			ops = append(ops, f.opView(rec))
+		}
+	}
+	return ops, nil
+}
+
+func (f *Client) OperationPages(orgId string, opts client.PageOptions) *client.Pager[cac.OperationOutput] {
+	return pages(func(ctx context.Context) ([]cac.OperationOutput, error) {
+		return f.ListOperations(ctx, orgId)
+	}, opts)
+}
+
+// pages - pages through the full list, which is read again for every page
+// like a paginating api would
+func pages[T any](list func(ctx context.Context) ([]T, error), opts client.PageOptions) *client.Pager[T] {
+	return client.NewPager(func(ctx context.Context, page client.Page) ([]T, bool, error) {
+		all, err := list(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		items, more := client.SlicePage(all, page)
+		return items, more, nil
+	}, opts)
+}
+
+func (f *Client) DescribeOperation(ctx context.Context, orgId, opId string) (*cac.OperationOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, err := f.op(orgId, opId)
+	if err != nil {
+		return nil, err
+	}
+	op := f.opView(rec)
+	return &op, nil
+}
+
+// CancelOperation - only operations that have not finished can be canceled
+func (f *Client) CancelOperation(ctx context.Context, orgId, opId string) (*cac.OperationOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, err := f.op(orgId, opId)
+	if err != nil {
+		return nil, err
+	}
+	switch status := f.opStatus(rec); status {
+	case cac.OPERATIONSTATUS_PENDING, cac.OPERATIONSTATUS_IN_PROGRESS, cac.OPERATIONSTATUS_PAUSED:
+	default:
+		return nil, conflict("operation %s is %s and can no longer be canceled", opId, status)
+	}
+	rec.canceledAt = f.Now()
+	op := f.opView(rec)
+	return &op, nil
+}
+
+// RetryOperation - starts a new operation of the same type for the same asset
+func (f *Client) RetryOperation(ctx context.Context, orgId, opId string) (*cac.OperationOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, err := f.op(orgId, opId)
+	if err != nil {
+		return nil, err
+	}
+	switch status := f.opStatus(rec); status {
+	case cac.OPERATIONSTATUS_FAILED, cac.OPERATIONSTATUS_CANCELED:
+	default:
+		return nil, conflict("operation %s is %s, only failed or canceled operations can be retried", opId, status)
+	}
+	retry := f.newOp(orgId, rec.assetId, *rec.op.OperationType.Get(), f.Now())
+	f.ops = append(f.ops, retry)
+	op := f.opView(retry)
+	return &op, nil
+}
+
+func (f *Client) CreateConnection(ctx context.Context, orgId, envId, assetId string, params cac.ConnectionInput) (*cac.ConnectionOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.asset(orgId, envId, assetId); err != nil {
+		return nil, err
+	}
+	if _, ok := f.assets[params.OutgoingAssetId]; !ok {
+		return nil, notFound("asset", params.OutgoingAssetId)
+	}
+
+	f.seq++
+	rec := &connRecord{
+		seq:           f.seq,
+		orgId:         orgId,
+		envId:         envId,
+		incomingAsset: assetId,
+		outgoingAsset: params.OutgoingAssetId,
+		conn: cac.ConnectionOutput{
+			Id: newId(),
+		},
+	}
+	f.conns[rec.conn.Id] = rec
+
+	conn := f.connView(rec)
+	return &conn, nil
+}
+
+func (f *Client) DescribeConnection(ctx context.Context, orgId, envId, assetId, connectionId string) (*cac.ConnectionOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, ok := f.conns[connectionId]
+	if !ok || rec.orgId != orgId || rec.envId != envId || rec.incomingAsset != assetId {
+		return nil, notFound("connection", connectionId)
+	}
+	conn := f.connView(rec)
+	return &conn, nil
+}
+
+func (f *Client) ListConnections(ctx context.Context, orgId, envId, assetId string) ([]cac.ConnectionOutput, error) {
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.env(orgId, envId); err != nil {
+		return nil, err
+	}
+	if assetId != "" {
+		if _, err := f.asset(orgId, envId, assetId); err != nil {
+			return nil, err
+		}
+	}
+
+	conns := []cac.ConnectionOutput{}
+	for _, rec := range f.sortedConns() {
+		if rec.orgId != orgId || rec.envId != envId {
+			continue
+		}
+		if assetId != "" && rec.incomingAsset != assetId {
+			continue
+		}
+		conns = append(conns, f.connView(rec))
+	}
+	return conns, nil
+}
+
+func (f *Client) DestroyConnection(ctx context.Context, orgId, envId, assetId, connectionId string) error {
+	if err := f.wait(ctx); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, ok := f.conns[connectionId]
+	if !ok || rec.orgId != orgId || rec.envId != envId || rec.incomingAsset != assetId {
+		return notFound("connection", connectionId)
+	}
+	delete(f.conns, connectionId)
+	return nil
+}
@@ -0,0 +1,321 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// clocked - a seeded fake whose clock only moves when the test says so
+func clocked() (*Client, func(d time.Duration)) {
+	f := NewSeeded()
+	now := time.Now()
+	f.Step = time.Minute
+	f.Now = func() time.Time { return now }
+	return f, func(d time.Duration) { now = now.Add(d) }
+}
+
+// lastOp - the most recent operation of asset
+func lastOp(t *testing.T, f *Client, assetId string) cac.OperationOutput {
+	ops, err := f.ListOperationsByAsset(context.Background(), DemoOrgId, assetId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ops) == 0 {
+		t.Fatalf("asset %s has no operations", assetId)
+	}
+	return ops[len(ops)-1]
+}
+
+func opStatus(op cac.OperationOutput) cac.OperationStatus {
+	if op.Status.Get() == nil {
+		return ""
+	}
+	return *op.Status.Get()
+}
+
+func TestStatusProgression(t *testing.T) {
+	ctx := context.Background()
+	f, advance := clocked()
+
+	asset, err := f.CreateAsset(ctx, DemoOrgId, DemoEnvId, cac.AssetInput{Asset: "aws__rds__latest", AssetVersion: "latest"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		elapsed   time.Duration
+		wantAsset cac.AssetStatus
+		wantOp    cac.OperationStatus
+	}{
+		{elapsed: 0, wantAsset: cac.ASSETSTATUS_REQUESTED, wantOp: cac.OPERATIONSTATUS_PENDING},
+		{elapsed: 30 * time.Second, wantAsset: cac.ASSETSTATUS_REQUESTED, wantOp: cac.OPERATIONSTATUS_PENDING},
+		{elapsed: 30 * time.Second, wantAsset: cac.ASSETSTATUS_DEPLOYING, wantOp: cac.OPERATIONSTATUS_IN_PROGRESS},
+		{elapsed: time.Minute, wantAsset: cac.ASSETSTATUS_DEPLOYED, wantOp: cac.OPERATIONSTATUS_COMPLETE},
+		{elapsed: time.Hour, wantAsset: cac.ASSETSTATUS_DEPLOYED, wantOp: cac.OPERATIONSTATUS_COMPLETE},
+	}
+	total := time.Duration(0)
+	for _, step := range steps {
+		advance(step.elapsed)
+		total += step.elapsed
+
+		got, err := f.DescribeAsset(ctx, DemoOrgId, DemoEnvId, asset.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != step.wantAsset {
+			t.Errorf("after %s asset is %s, want %s", total, got.Status, step.wantAsset)
+		}
+		op, err := f.DescribeOperation(ctx, DemoOrgId, lastOp(t, f, asset.Id).Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := opStatus(*op); status != step.wantOp {
+			t.Errorf("after %s operation is %s, want %s", total, status, step.wantOp)
+		}
+	}
+
+	if err := f.DestroyAsset(ctx, DemoOrgId, DemoEnvId, asset.Id); err != nil {
+		t.Fatal(err)
+	}
+	destroy := lastOp(t, f, asset.Id)
+	if opType := destroy.OperationType.Get(); opType == nil || *opType != cac.OPERATIONTYPE_DESTROY {
+		t.Errorf("destroy started a %v operation", opType)
+	}
+	for _, want := range []cac.AssetStatus{cac.ASSETSTATUS_DESTROYING, cac.ASSETSTATUS_DESTROYED} {
+		got, err := f.DescribeAsset(ctx, DemoOrgId, DemoEnvId, asset.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != want {
+			t.Errorf("destroyed asset is %s, want %s", got.Status, want)
+		}
+		advance(time.Minute)
+	}
+}
+
+func TestUpdateAssetOperation(t *testing.T) {
+	ctx := context.Background()
+	f, advance := clocked()
+	advance(time.Hour)
+
+	asset, opId, err := f.UpdateAsset(ctx, DemoOrgId, DemoEnvId, DemoVpcId, cac.AssetInput{
+		AssetParameters: map[string]interface{}{"name": "demo-vpc-2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if asset.Status != cac.ASSETSTATUS_REQUESTED {
+		t.Errorf("updated asset is %s, want it redeploying", asset.Status)
+	}
+	if last := lastOp(t, f, DemoVpcId); opId == "" || last.Id != opId {
+		t.Errorf("update returned operation %q, the asset's newest is %s", opId, last.Id)
+	}
+}
+
+func TestSeededFailure(t *testing.T) {
+	f := NewSeeded()
+	ops, err := f.ListOperationsByAsset(context.Background(), DemoOrgId, DemoVpcId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []cac.OperationStatus{cac.OPERATIONSTATUS_FAILED, cac.OPERATIONSTATUS_COMPLETE}
+	if len(ops) != len(want) {
+		t.Fatalf("demo vpc has %d operations, want %d", len(ops), len(want))
+	}
+	for i, op := range ops {
+		if status := opStatus(op); status != want[i] {
+			t.Errorf("operation %d is %s, want %s", i, status, want[i])
+		}
+	}
+	if ops[0].ErrorMessage == nil || *ops[0].ErrorMessage == "" {
+		t.Error("failed operation has no error message")
+	}
+}
+
+func TestCancelAndRetry(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		// elapsed - how long the operation ran before it is canceled
+		elapsed    time.Duration
+		wantCancel cac.OperationStatus
+		wantErr    client.ErrorKind
+	}{
+		{name: "pending", elapsed: 0, wantCancel: cac.OPERATIONSTATUS_CANCELED},
+		{name: "in progress", elapsed: 90 * time.Second, wantCancel: cac.OPERATIONSTATUS_CANCELED},
+		{name: "complete", elapsed: time.Hour, wantErr: client.KindConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, advance := clocked()
+			asset, err := f.CreateAsset(ctx, DemoOrgId, DemoEnvId, cac.AssetInput{Asset: "aws__rds__latest"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			opId := lastOp(t, f, asset.Id).Id
+			advance(tt.elapsed)
+
+			op, err := f.CancelOperation(ctx, DemoOrgId, opId)
+			if tt.wantErr != client.KindUnknown {
+				var apiErr *client.APIError
+				if !errors.As(err, &apiErr) || apiErr.Kind != tt.wantErr {
+					t.Fatalf("err = %v, want kind %d", err, tt.wantErr)
+				}
+				// only failed or canceled operations are retried
+				if _, err := f.RetryOperation(ctx, DemoOrgId, opId); !errors.As(err, &apiErr) || apiErr.Kind != client.KindConflict {
+					t.Errorf("retrying a complete operation: err = %v, want a conflict", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status := opStatus(*op); status != tt.wantCancel {
+				t.Errorf("canceled operation is %s, want %s", status, tt.wantCancel)
+			}
+
+			retry, err := f.RetryOperation(ctx, DemoOrgId, opId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if retry.Id == opId || opStatus(*retry) != cac.OPERATIONSTATUS_PENDING {
+				t.Errorf("retry = %s %s, want a new pending operation", retry.Id, opStatus(*retry))
+			}
+		})
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	ctx := context.Background()
+	f := NewSeeded()
+	other, err := f.CreateOrg(ctx, cac.OrganizationInput{Name: "Other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const missing = "00000000-0000-4000-8000-0000000000ff"
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "org", call: func() error { _, err := f.FindOrg(ctx, missing); return err }},
+		{name: "update org", call: func() error { _, err := f.UpdateOrg(ctx, missing, cac.OrganizationInput{}); return err }},
+		{name: "environments of an org", call: func() error { _, err := f.ListEnvironments(ctx, missing); return err }},
+		{name: "environment", call: func() error { _, err := f.DescribeEnvironment(ctx, DemoOrgId, missing); return err }},
+		{name: "environment of another org", call: func() error { _, err := f.DescribeEnvironment(ctx, other.Id, DemoEnvId); return err }},
+		{name: "destroy environment", call: func() error { return f.DestroyEnvironment(ctx, DemoOrgId, missing) }},
+		{name: "assets of an environment", call: func() error { _, err := f.ListAssets(ctx, DemoOrgId, missing); return err }},
+		{name: "asset", call: func() error { _, err := f.DescribeAsset(ctx, DemoOrgId, DemoEnvId, missing); return err }},
+		{name: "asset in another org", call: func() error { _, err := f.DescribeAsset(ctx, other.Id, DemoEnvId, DemoVpcId); return err }},
+		{name: "update asset", call: func() error {
+			_, _, err := f.UpdateAsset(ctx, DemoOrgId, DemoEnvId, missing, cac.AssetInput{})
+			return err
+		}},
+		{name: "destroy asset", call: func() error { return f.DestroyAsset(ctx, DemoOrgId, DemoEnvId, missing) }},
+		{name: "operation", call: func() error { _, err := f.DescribeOperation(ctx, DemoOrgId, missing); return err }},
+		{name: "cancel operation", call: func() error { _, err := f.CancelOperation(ctx, DemoOrgId, missing); return err }},
+		{name: "connection", call: func() error {
+			_, err := f.DescribeConnection(ctx, DemoOrgId, DemoEnvId, DemoVpcId, missing)
+			return err
+		}},
+		{name: "connection to a missing asset", call: func() error {
+			_, err := f.CreateConnection(ctx, DemoOrgId, DemoEnvId, DemoVpcId, cac.ConnectionInput{OutgoingAssetId: missing})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			var apiErr *client.APIError
+			if !errors.As(err, &apiErr) || apiErr.Kind != client.KindNotFound || apiErr.StatusCode != 404 {
+				t.Fatalf("err = %v, want a 404 api error", err)
+			}
+			if code := client.ExitCode(err); code != 3 {
+				t.Errorf("exit code = %d, want 3", code)
+			}
+		})
+	}
+}
+
+func TestLatencyHonorsCancellation(t *testing.T) {
+	f := NewSeeded()
+	f.Latency = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := f.ListOrgs(ctx)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("a canceled call waited out the latency")
+	}
+}
+
+// TestConcurrentAccess - meant to be run with -race
+func TestConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	f := NewSeeded()
+	const workers = 8
+	const perWorker = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*perWorker*3)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				env, err := f.CreateEnvironment(ctx, DemoOrgId, cac.EnvironmentInput{Name: fmt.Sprintf("env-%d-%d", w, i)})
+				if err != nil {
+					errs <- err
+					continue
+				}
+				if _, err := f.CreateAsset(ctx, DemoOrgId, env.Id, cac.AssetInput{Asset: "aws__vpc__latest"}); err != nil {
+					errs <- err
+				}
+				if _, err := f.ListEnvironments(ctx, DemoOrgId); err != nil {
+					errs <- err
+				}
+				if _, err := f.ListOperations(ctx, DemoOrgId); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	envs, err := f.ListEnvironments(ctx, DemoOrgId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envs) != 1+workers*perWorker {
+		t.Errorf("%d environments, want %d", len(envs), 1+workers*perWorker)
+	}
+	ops, err := f.ListOperations(ctx, DemoOrgId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the seeded vpc has two operations, every new asset one
+	if len(ops) != 2+workers*perWorker {
+		t.Errorf("%d operations, want %d", len(ops), 2+workers*perWorker)
+	}
+}
@@ -0,0 +1,350 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// APIPrefix - every cloud api route lives under this path
+const APIPrefix = "/api/v1"
+
+type params map[string]string
+
+type handlerFn func(r *http.Request, p params) (interface{}, error)
+
+// located - a response body sent with a Location header, e.g. pointing at
+// the operation a request started
+type located struct {
+	body     interface{}
+	location string
+}
+
+type route struct {
+	method  string
+	pattern []string
+	handle  handlerFn
+}
+
+// Server - serves the cloud api routes used by the cac client from a fake
+// Client so the cli, or anything else speaking the api, can run against it
+type Server struct {
+	// Log - when set every request is written to it
+	Log io.Writer
+	// PageSize - items per page of a list when the request does not send
+	// per_page, 0 sends lists whole
+	PageSize int
+
+	fake   *Client
+	routes []route
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// NewServer - http.Handler backed by f, assets and operations progress on
+// f's clock the same way they do for the in-process fake
+func NewServer(f *Client) *Server {
+	s := &Server{fake: f}
+
+	s.handle("GET", "/organizations", s.listOrgs)
+	s.handle("POST", "/organizations", s.createOrg)
+	s.handle("GET", "/organizations/{org}", s.findOrg)
+	s.handle("PUT", "/organizations/{org}", s.updateOrg)
+
+	s.handle("GET", "/organizations/{org}/environments", s.listEnvs)
+	s.handle("POST", "/organizations/{org}/environments", s.createEnv)
+	s.handle("GET", "/organizations/{org}/environments/{env}", s.describeEnv)
+	s.handle("PUT", "/organizations/{org}/environments/{env}", s.updateEnv)
+	s.handle("DELETE", "/organizations/{org}/environments/{env}", s.destroyEnv)
+	s.handle("GET", "/organizations/{org}/environments/{env}/asset_bundles", s.listBundles)
+
+	s.handle("GET", "/organizations/{org}/environments/{env}/assets", s.listAssets)
+	s.handle("POST", "/organizations/{org}/environments/{env}/assets", s.createAsset)
+	s.handle("GET", "/organizations/{org}/environments/{env}/assets/{asset}", s.describeAsset)
+	s.handle("PUT", "/organizations/{org}/environments/{env}/assets/{asset}", s.updateAsset)
+	s.handle("DELETE", "/organizations/{org}/environments/{env}/assets/{asset}", s.destroyAsset)
+
+	s.handle("POST", "/organizations/{org}/environments/{env}/assets/{asset}/connections", s.createConn)
+	s.handle("GET", "/organizations/{org}/environments/{env}/assets/{asset}/connections/{conn}", s.describeConn)
+	s.handle("DELETE", "/organizations/{org}/environments/{env}/assets/{asset}/connections/{conn}", s.destroyConn)
+
+	s.handle("GET", "/organizations/{org}/operations", s.listOps)
+	s.handle("GET", "/organizations/{org}/operations/{op}", s.describeOp)
+	s.handle("POST", "/organizations/{org}/operations/{op}/cancel", s.cancelOp)
+	s.handle("POST", "/organizations/{org}/operations/{op}/retry", s.retryOp)
+
+	return s
+}
+
+func (s *Server) handle(method, pattern string, fn handlerFn) {
+	s.routes = append(s.routes, route{
+		method:  method,
+		pattern: strings.Split(strings.Trim(APIPrefix+pattern, "/"), "/"),
+		handle:  fn,
+	})
+}
+
+// match - the route for the request and the values of its {placeholders},
+// found reports whether the path exists with some other method
+func (s *Server) match(method, path string) (*route, params, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	found := false
+	for i := range s.routes {
+		rt := &s.routes[i]
+		if len(rt.pattern) != len(segments) {
+			continue
+		}
+		p := params{}
+		ok := true
+		for j, part := range rt.pattern {
+			if strings.HasPrefix(part, "{") {
+				p[strings.Trim(part, "{}")] = segments[j]
+			} else if part != segments[j] {
+				ok = false
+				break
+			}
+		}
+		if !ok {
+			continue
+		}
+		found = true
+		if rt.method == method {
+			return rt, p, true
+		}
+	}
+	return nil, nil, found
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	status := s.serve(w, r)
+	if s.Log != nil {
+		fmt.Fprintf(s.Log, "%s %s %s -> %d (%s)\n", start.Format(time.RFC3339), r.Method, r.URL.RequestURI(), status, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) int {
+	rt, p, found := s.match(r.Method, r.URL.Path)
+	if rt == nil {
+		if found {
+			return writeError(w, http.StatusMethodNotAllowed, "Method Not Allowed")
+		}
+		return writeError(w, http.StatusNotFound, "Not Found")
+	}
+	// any token is accepted, but like the real api one has to be sent
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ") {
+		return writeError(w, http.StatusUnauthorized, "Not authenticated")
+	}
+
+	body, err := rt.handle(r, p)
+	if err != nil {
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode != 0 {
+			return writeError(w, apiErr.StatusCode, apiErr.Message)
+		}
+		return writeError(w, http.StatusInternalServerError, err.Error())
+	}
+	if l, ok := body.(located); ok {
+		w.Header().Set("Location", l.location)
+		body = l.body
+	}
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	return writeJSON(w, http.StatusOK, s.paginate(w, r, body))
+}
+
+// paginate - lists are paged by PageSize or the request's per_page, and
+// page picks the page.  Further pages are announced with a Link header,
+// which is all the client follows, and the total with X-Total-Count.
+func (s *Server) paginate(w http.ResponseWriter, r *http.Request, body interface{}) interface{} {
+	list := reflect.ValueOf(body)
+	if list.Kind() != reflect.Slice {
+		return body
+	}
+	query := r.URL.Query()
+	size, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || size < 1 {
+		size = s.PageSize
+	}
+	if size < 1 {
+		return body
+	}
+	number, err := strconv.Atoi(query.Get("page"))
+	if err != nil || number < 1 {
+		number = 1
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(list.Len()))
+	start := (number - 1) * size
+	if start > list.Len() {
+		start = list.Len()
+	}
+	end := start + size
+	if end < list.Len() {
+		next := *r.URL
+		query.Set("page", strconv.Itoa(number+1))
+		query.Set("per_page", strconv.Itoa(size))
+		next.RawQuery = query.Encode()
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+	} else {
+		end = list.Len()
+	}
+	return list.Slice(start, end).Interface()
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) int {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+	return status
+}
+
+// writeError - same shape as the api's own errors, see client.APIError
+func writeError(w http.ResponseWriter, status int, detail string) int {
+	return writeJSON(w, status, map[string]string{"detail": detail})
+}
+
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return &client.APIError{
+			Kind:       client.KindValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    fmt.Sprintf("invalid request body: %s", err),
+		}
+	}
+	return nil
+}
+
+func (s *Server) listOrgs(r *http.Request, p params) (interface{}, error) {
+	return s.fake.ListOrgs(r.Context())
+}
+
+func (s *Server) createOrg(r *http.Request, p params) (interface{}, error) {
+	var input cac.OrganizationInput
+	if err := decode(r, &input); err != nil {
+		return nil, err
+	}
+	return s.fake.CreateOrg(r.Context(), input)
+}
+
+func (s *Server) findOrg(r *http.Request, p params) (interface{}, error) {
+	return s.fake.FindOrg(r.Context(), p["org"])
+}
+
+func (s *Server) updateOrg(r *http.Request, p params) (interface{}, error) {
+	var input cac.OrganizationInput
+	if err := decode(r, &input); err != nil {
+		return nil, err
+	}
+	return s.fake.UpdateOrg(r.Context(), p["org"], input)
+}
+
+func (s *Server) listEnvs(r *http.Request, p params) (interface{}, error) {
+	return s.fake.ListEnvironments(r.Context(), p["org"])
+}
+
+func (s *Server) createEnv(r *http.Request, p params) (interface{}, error) {
+	var input cac.EnvironmentInput
+	if err := decode(r, &input); err != nil {
+		return nil, err
+	}
+	return s.fake.CreateEnvironment(r.Context(), p["org"], input)
+}
+
+func (s *Server) describeEnv(r *http.Request, p params) (interface{}, error) {
+	return s.fake.DescribeEnvironment(r.Context(), p["org"], p["env"])
+}
+
+func (s *Server) updateEnv(r *http.Request, p params) (interface{}, error) {
+	var input cac.EnvironmentInput
+	if err := decode(r, &input); err != nil {
+		return nil, err
+	}
+	return s.fake.UpdateEnvironment(r.Context(), p["org"], p["env"], input)
+}
+
+func (s *Server) destroyEnv(r *http.Request, p params) (interface{}, error) {
+	return nil, s.fake.DestroyEnvironment(r.Context(), p["org"], p["env"])
+}
+
+func (s *Server) listBundles(r *http.Request, p params) (interface{}, error) {
+	return s.fake.ListAssetBundles(r.Context(), p["org"], p["env"])
+}
+
+func (s *Server) listAssets(r *http.Request, p params) (interface{}, error) {
+	return s.fake.ListAssets(r.Context(), p["org"], p["env"])
+}
+
+func (s *Server) createAsset(r *http.Request, p params) (interface{}, error) {
+	var input cac.AssetInput
+	if err := decode(r, &input); err != nil {
+		return nil, err
+	}
+	return s.fake.CreateAsset(r.Context(), p["org"], p["env"], input)
+}
+
+func (s *Server) describeAsset(r *http.Request, p params) (interface{}, error) {
+	return s.fake.DescribeAsset(r.Context(), p["org"], p["env"], p["asset"])
+}
+
+func (s *Server) updateAsset(r *http.Request, p params) (interface{}, error) {
+	var input cac.AssetInput
+	if err := decode(r, &input); err != nil {
+		return nil, err
+	}
+	asset, opId, err := s.fake.UpdateAsset(r.Context(), p["org"], p["env"], p["asset"], input)
+	if err != nil {
+		return nil, err
+	}
+	return located{body: asset, location: fmt.Sprintf("%s/organizations/%s/operations/%s", APIPrefix, p["org"], opId)}, nil
+}
+
+func (s *Server) destroyAsset(r *http.Request, p params) (interface{}, error) {
+	return nil, s.fake.DestroyAsset(r.Context(), p["org"], p["env"], p["asset"])
+}
+
+func (s *Server) createConn(r *http.Request, p params) (interface{}, error) {
+	var input cac.ConnectionInput
+	if err := decode(r, &input); err != nil {
+		return nil, err
+	}
+	return s.fake.CreateConnection(r.Context(), p["org"], p["env"], p["asset"], input)
+}
+
+func (s *Server) describeConn(r *http.Request, p params) (interface{}, error) {
+	return s.fake.DescribeConnection(r.Context(), p["org"], p["env"], p["asset"], p["conn"])
+}
+
+func (s *Server) destroyConn(r *http.Request, p params) (interface{}, error) {
+	return nil, s.fake.DestroyConnection(r.Context(), p["org"], p["env"], p["asset"], p["conn"])
+}
+
+func (s *Server) listOps(r *http.Request, p params) (interface{}, error) {
+	if assetId := r.URL.Query().Get("asset_id"); assetId != "" {
+		return s.fake.ListOperationsByAsset(r.Context(), p["org"], assetId)
+	}
+	return s.fake.ListOperations(r.Context(), p["org"])
+}
+
+func (s *Server) describeOp(r *http.Request, p params) (interface{}, error) {
+	return s.fake.DescribeOperation(r.Context(), p["org"], p["op"])
+}
+
+func (s *Server) cancelOp(r *http.Request, p params) (interface{}, error) {
+	return s.fake.CancelOperation(r.Context(), p["org"], p["op"])
+}
+
+func (s *Server) retryOp(r *http.Request, p params) (interface{}, error) {
+	return s.fake.RetryOperation(r.Context(), p["org"], p["op"])
+}
@@ -0,0 +1,183 @@
+package fake
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+)
+
+// request - an api request the way the cac client sends it
+func request(t *testing.T, server *httptest.Server, method, path, token string) *http.Response {
+	req, err := http.NewRequest(method, server.URL+APIPrefix+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestServerPaginate(t *testing.T) {
+	f := NewSeeded()
+	for i := 1; i <= 4; i++ {
+		if _, err := f.CreateEnvironment(context.Background(), DemoOrgId, cac.EnvironmentInput{Name: fmt.Sprintf("env-%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server := httptest.NewServer(NewServer(f))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		query     string
+		wantCount int
+		wantTotal string
+		wantNext  string
+	}{
+		{name: "not paginated", query: "", wantCount: 5},
+		{name: "first page", query: "?page=1&per_page=2", wantCount: 2, wantTotal: "5", wantNext: "page=2"},
+		{name: "middle page", query: "?page=2&per_page=2", wantCount: 2, wantTotal: "5", wantNext: "page=3"},
+		{name: "last page", query: "?page=3&per_page=2", wantCount: 1, wantTotal: "5"},
+		{name: "exact fit", query: "?page=1&per_page=5", wantCount: 5, wantTotal: "5"},
+		{name: "past the end", query: "?page=9&per_page=2", wantCount: 0, wantTotal: "5"},
+		{name: "page defaults to 1", query: "?per_page=3", wantCount: 3, wantTotal: "5", wantNext: "page=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := request(t, server, "GET", "/organizations/"+DemoOrgId+"/environments"+tt.query, "token")
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d", resp.StatusCode)
+			}
+
+			var envs []cac.EnvironmentOutput
+			if err := json.NewDecoder(resp.Body).Decode(&envs); err != nil {
+				t.Fatal(err)
+			}
+			if len(envs) != tt.wantCount {
+				t.Errorf("got %d environments, want %d", len(envs), tt.wantCount)
+			}
+			if got := resp.Header.Get("X-Total-Count"); got != tt.wantTotal {
+				t.Errorf("X-Total-Count = %q, want %q", got, tt.wantTotal)
+			}
+			link := resp.Header.Get("Link")
+			if tt.wantNext == "" && link != "" {
+				t.Errorf("Link = %q, want none", link)
+			}
+			if tt.wantNext != "" && (!strings.Contains(link, tt.wantNext) || !strings.HasSuffix(link, `rel="next"`)) {
+				t.Errorf("Link = %q, want a next link to %s", link, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestServerPageSize(t *testing.T) {
+	f := NewSeeded()
+	for i := 1; i <= 2; i++ {
+		if _, err := f.CreateEnvironment(context.Background(), DemoOrgId, cac.EnvironmentInput{Name: fmt.Sprintf("env-%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	api := NewServer(f)
+	api.PageSize = 1
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	// a request without paging parameters is paged by the server, and its
+	// links alone lead through the list one item at a time
+	names := []string{}
+	path := "/organizations/" + DemoOrgId + "/environments"
+	for requests := 0; path != ""; requests++ {
+		if requests > 3 {
+			t.Fatal("the links never end")
+		}
+		resp := request(t, server, "GET", path, "token")
+		var envs []cac.EnvironmentOutput
+		err := json.NewDecoder(resp.Body).Decode(&envs)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(envs) != 1 {
+			t.Fatalf("page of %d environments, want 1", len(envs))
+		}
+		names = append(names, envs[0].Name)
+
+		path = ""
+		if link := resp.Header.Get("Link"); link != "" {
+			path = strings.TrimPrefix(link[1:strings.Index(link, ">")], APIPrefix)
+		}
+	}
+	if want := []string{"demo", "env-1", "env-2"}; strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("environments = %v, want %v", names, want)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	server := httptest.NewServer(NewServer(NewSeeded()))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{name: "list", method: "GET", path: "/organizations", token: "token", want: http.StatusOK},
+		{name: "no token", method: "GET", path: "/organizations", want: http.StatusUnauthorized},
+		{name: "unknown org", method: "GET", path: "/organizations/00000000-0000-4000-8000-0000000000ff", token: "token", want: http.StatusNotFound},
+		{name: "unknown route", method: "GET", path: "/nope", token: "token", want: http.StatusNotFound},
+		{name: "wrong method", method: "PATCH", path: "/organizations", token: "token", want: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := request(t, server, tt.method, tt.path, tt.token)
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestServerUpdateAssetLocation(t *testing.T) {
+	f := NewSeeded()
+	server := httptest.NewServer(NewServer(f))
+	defer server.Close()
+
+	path := "/organizations/" + DemoOrgId + "/environments/" + DemoEnvId + "/assets/" + DemoVpcId
+	req, err := http.NewRequest("PUT", server.URL+APIPrefix+path, strings.NewReader(`{"asset_parameters": {"name": "demo-vpc-2"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+
+	ops, err := f.ListOperationsByAsset(context.Background(), DemoOrgId, DemoVpcId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := APIPrefix + "/organizations/" + DemoOrgId + "/operations/" + ops[len(ops)-1].Id
+	if got := resp.Header.Get("Location"); got != want {
+		t.Errorf("Location = %q, want %q", got, want)
+	}
+}
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"context"
+
 	cac "github.com/aptible/cloud-api-clients/clients/go"
 )
 
@@ -8,27 +10,47 @@ import (
 CloudClient
 The goal of this interface is to be an abstraction layer above the cloud-api.
 Whenever we want to interface with the API, we should use this interface.
+Every method takes a context so callers can cancel in-flight requests or
+attach a deadline to them.  The *Pages methods only build a Pager, the context
+is passed to each call of Pager.Next instead, while the matching List* methods
+collect every page.
 */
 type CloudClient interface {
-	ListEnvironments(orgId string) ([]cac.EnvironmentOutput, error)
-	CreateEnvironment(orgId string, params cac.EnvironmentInput) (*cac.EnvironmentOutput, error)
-	DestroyEnvironment(orgId, envId string) error
+	ListEnvironments(ctx context.Context, orgId string) ([]cac.EnvironmentOutput, error)
+	EnvironmentPages(orgId string, opts PageOptions) *Pager[cac.EnvironmentOutput]
+	CreateEnvironment(ctx context.Context, orgId string, params cac.EnvironmentInput) (*cac.EnvironmentOutput, error)
+	DescribeEnvironment(ctx context.Context, orgId, envId string) (*cac.EnvironmentOutput, error)
+	UpdateEnvironment(ctx context.Context, orgId, envId string, params cac.EnvironmentInput) (*cac.EnvironmentOutput, error)
+	DestroyEnvironment(ctx context.Context, orgId, envId string) error
 
-	ListOrgs() ([]cac.OrganizationOutput, error)
-	CreateOrg(orgId string, params cac.OrganizationInput) (*cac.OrganizationOutput, error)
-	FindOrg(orgId string) (*cac.OrganizationOutput, error)
+	ListOrgs(ctx context.Context) ([]cac.OrganizationOutput, error)
+	OrgPages(opts PageOptions) *Pager[cac.OrganizationOutput]
+	CreateOrg(ctx context.Context, params cac.OrganizationInput) (*cac.OrganizationOutput, error)
+	UpdateOrg(ctx context.Context, orgId string, params cac.OrganizationInput) (*cac.OrganizationOutput, error)
+	FindOrg(ctx context.Context, orgId string) (*cac.OrganizationOutput, error)
 
-	ListAssetBundles(orgId, envId string) ([]cac.AssetBundle, error)
-	CreateAsset(orgId, envId string, params cac.AssetInput) (*cac.AssetOutput, error)
-	ListAssets(orgId, envId string) ([]cac.AssetOutput, error)
-	DescribeAsset(orgId, envId, assetId string) (*cac.AssetOutput, error)
+	ListAssetBundles(ctx context.Context, orgId, envId string) ([]cac.AssetBundle, error)
+	CreateAsset(ctx context.Context, orgId, envId string, params cac.AssetInput) (*cac.AssetOutput, error)
+	ListAssets(ctx context.Context, orgId, envId string) ([]cac.AssetOutput, error)
+	AssetPages(orgId, envId string, opts PageOptions) *Pager[cac.AssetOutput]
+	DescribeAsset(ctx context.Context, orgId, envId, assetId string) (*cac.AssetOutput, error)
 	//ListAssetTypesForEnvironment(envId string) error
-	// UpdateAsset(orgId string, envId string, assetID string, assetInput *client.AssetInput) error
-	DestroyAsset(orgId, envId, assetID string) error
+	// UpdateAsset - opId is the operation the update started, empty when
+	// the api does not say which one it is
+	UpdateAsset(ctx context.Context, orgId, envId, assetId string, params cac.AssetInput) (asset *cac.AssetOutput, opId string, err error)
+	DestroyAsset(ctx context.Context, orgId, envId, assetID string) error
 
-	ListOperationsByAsset(orgId, assetId string) ([]cac.OperationOutput, error)
+	ListOperations(ctx context.Context, orgId string) ([]cac.OperationOutput, error)
+	OperationPages(orgId string, opts PageOptions) *Pager[cac.OperationOutput]
+	ListOperationsByAsset(ctx context.Context, orgId, assetId string) ([]cac.OperationOutput, error)
+	OperationPagesByAsset(orgId, assetId string, opts PageOptions) *Pager[cac.OperationOutput]
+	DescribeOperation(ctx context.Context, orgId, opId string) (*cac.OperationOutput, error)
+	CancelOperation(ctx context.Context, orgId, opId string) (*cac.OperationOutput, error)
+	RetryOperation(ctx context.Context, orgId, opId string) (*cac.OperationOutput, error)
 
-	CreateConnection(orgId, envId, assetId string, params cac.ConnectionInput) (*cac.ConnectionOutput, error)
-	// DescribeConnection(orgId, envId, assetId, connectionId string) (cac.ConnectionOutput, error)
-	DestroyConnection(orgId, envId, assetId, connectionId string) error
+	CreateConnection(ctx context.Context, orgId, envId, assetId string, params cac.ConnectionInput) (*cac.ConnectionOutput, error)
+	DescribeConnection(ctx context.Context, orgId, envId, assetId, connectionId string) (*cac.ConnectionOutput, error)
+	// ListConnections - connections of a single asset, or of the whole environment when assetId is empty
+	ListConnections(ctx context.Context, orgId, envId, assetId string) ([]cac.ConnectionOutput, error)
+	DestroyConnection(ctx context.Context, orgId, envId, assetId, connectionId string) error
 }
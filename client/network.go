@@ -0,0 +1,110 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Network - how requests reach the api and the auth server, for networks
+// that need a proxy, a TLS-inspecting CA or client certificates
+type Network struct {
+	// Proxy - url of the proxy, when empty HTTPS_PROXY and friends apply
+	Proxy string
+	// CACert - PEM bundle trusted on top of the system roots
+	CACert string
+	// ClientCert, ClientKey - PEM certificate and key presented for mTLS
+	ClientCert string
+	ClientKey  string
+	// InsecureSkipVerify - accept any server certificate, only for debugging
+	InsecureSkipVerify bool
+}
+
+// Transport - a copy of http.DefaultTransport with the settings applied
+func (n Network) Transport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if n.Proxy != "" {
+		proxy, err := url.Parse(n.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("proxy %q is not a valid url, e.g. http://proxy.example.com:3128", n.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: n.InsecureSkipVerify,
+	}
+	if n.CACert != "" {
+		pem, err := os.ReadFile(n.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read ca-cert: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca-cert %s does not contain any PEM certificates", n.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if n.ClientCert != "" || n.ClientKey != "" {
+		if n.ClientCert == "" || n.ClientKey == "" {
+			return nil, fmt.Errorf("client-cert and client-key have to be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(n.ClientCert, n.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client-cert: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
+
+// Describe - the effective settings for requests to target, one per line
+func (n Network) Describe(target *url.URL) string {
+	proxy := "none"
+	if n.Proxy != "" {
+		if u, err := url.Parse(n.Proxy); err == nil {
+			proxy = u.Redacted()
+		}
+	} else if u, err := http.ProxyFromEnvironment(&http.Request{URL: target}); err == nil && u != nil {
+		proxy = u.Redacted() + " (from environment)"
+	}
+
+	caCert := "system roots"
+	if n.CACert != "" {
+		caCert = n.CACert + " and system roots"
+	}
+
+	clientCert := "none"
+	if n.ClientCert != "" {
+		clientCert = fmt.Sprintf("%s (key %s)", n.ClientCert, n.ClientKey)
+	}
+
+	lines := []string{
+		fmt.Sprintf("proxy: %s", proxy),
+		fmt.Sprintf("ca-cert: %s", caCert),
+		fmt.Sprintf("client-cert: %s", clientCert),
+		fmt.Sprintf("insecure-skip-verify: %t", n.InsecureSkipVerify),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// errTransport - fails every request, used when the network settings are
+// invalid so they are never silently ignored
+type errTransport struct {
+	err error
+}
+
+func (t errTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, t.err
+}
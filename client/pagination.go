@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultPageSize - items per page when PageOptions.Size is not set
+const DefaultPageSize = 100
+
+// PageOptions - how a list is fetched, the zero value fetches every item
+type PageOptions struct {
+	// Size - items per page of a list held in memory, such as the fake's.
+	// The api picks its own page size, see pageLinks.
+	Size int
+	// Limit - stop once this many items were read, 0 means no limit
+	Limit int
+}
+
+// Validate - rejects negative sizes and limits
+func (o PageOptions) Validate() error {
+	if o.Size < 0 {
+		return fmt.Errorf("page size must not be negative, got %d", o.Size)
+	}
+	if o.Limit < 0 {
+		return fmt.Errorf("limit must not be negative, got %d", o.Limit)
+	}
+	return nil
+}
+
+// Page - a single page of a list, Number starts at 1.  Only lists held in
+// memory are sliced by it, the api client follows the api's links instead.
+type Page struct {
+	Number int
+	Size   int
+}
+
+// FetchPage - requests one page, more reports whether the api has another after it
+type FetchPage[T any] func(ctx context.Context, page Page) (items []T, more bool, err error)
+
+// Pager - streams a list one page at a time
+//
+//	for !pager.Done() {
+//		items, err := pager.Next(ctx)
+//		...
+//	}
+type Pager[T any] struct {
+	fetch FetchPage[T]
+	opts  PageOptions
+	page  int
+	count int
+	done  bool
+}
+
+// NewPager - nothing is requested until the first call to Next
+func NewPager[T any](fetch FetchPage[T], opts PageOptions) *Pager[T] {
+	if opts.Size <= 0 {
+		opts.Size = DefaultPageSize
+	}
+	if opts.Limit > 0 && opts.Limit < opts.Size {
+		// no point asking for items that would be thrown away
+		opts.Size = opts.Limit
+	}
+	return &Pager[T]{fetch: fetch, opts: opts}
+}
+
+// Done - whether every page, or every item up to the limit, was read
+func (p *Pager[T]) Done() bool {
+	return p.done
+}
+
+// Stop - marks the pager done so no further pages are requested
+func (p *Pager[T]) Stop() {
+	p.done = true
+}
+
+// Next - the items of the next page, nil once the pager is done
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	p.page++
+	items, more, err := p.fetch(ctx, Page{Number: p.page, Size: p.opts.Size})
+	if err != nil {
+		p.done = true
+		return nil, err
+	}
+
+	if p.opts.Limit > 0 && p.count+len(items) >= p.opts.Limit {
+		items = items[:p.opts.Limit-p.count]
+		more = false
+	}
+	p.count += len(items)
+	// an empty page can never lead anywhere, stop instead of looping forever
+	if !more || len(items) == 0 {
+		p.done = true
+	}
+	return items, nil
+}
+
+// Collect - reads every remaining page of the pager
+func Collect[T any](ctx context.Context, pager *Pager[T]) ([]T, error) {
+	all := []T{}
+	for !pager.Done() {
+		items, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+	return all, nil
+}
+
+// SlicePage - the part of items that falls on page, for fakes that hold the
+// whole list in memory
+func SlicePage[T any](items []T, page Page) ([]T, bool) {
+	start := (page.Number - 1) * page.Size
+	if start >= len(items) {
+		return []T{}, false
+	}
+	end := start + page.Size
+	if end >= len(items) {
+		return items[start:], false
+	}
+	return items[start:end], true
+}
+
+type pageKey struct{}
+
+// pageLinks - where the next page of a list is.  Paging is not in the api's
+// published spec and the generated client has no paging parameters, so
+// none are sent: the first request goes out as the generated client builds
+// it and further pages are only read when the response links to them with
+// a `Link: <...>; rel="next"` header.  A response without one is the whole
+// list.
+type pageLinks struct {
+	next string
+}
+
+// context - asks the page transport to request the next page instead of
+// the url the generated client builds
+func (l *pageLinks) context(ctx context.Context) context.Context {
+	if l.next == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, pageKey{}, l.next)
+}
+
+// update - reads the link to the next page from r, reporting whether there
+// is one.  A link back to the page just read ends the list rather than
+// reading it forever.
+func (l *pageLinks) update(r *http.Response) bool {
+	next := nextLink(r)
+	if next == l.next {
+		next = ""
+	}
+	l.next = next
+	return next != ""
+}
+
+// nextLink - the target of the rel="next" Link header, empty when there is none
+func nextLink(r *http.Response) string {
+	if r == nil {
+		return ""
+	}
+	for _, link := range r.Header.Values("Link") {
+		for _, part := range strings.Split(link, ",") {
+			target, params, ok := strings.Cut(part, ";")
+			if !ok || !strings.Contains(params, `rel="next"`) {
+				continue
+			}
+			target = strings.TrimSpace(target)
+			if strings.HasPrefix(target, "<") && strings.HasSuffix(target, ">") {
+				return target[1 : len(target)-1]
+			}
+		}
+	}
+	return ""
+}
+
+// pageTransport - sends requests whose context carries a next page link to
+// that link instead, which has to be on the host the request was for so
+// the token is never sent anywhere else
+type pageTransport struct {
+	next http.RoundTripper
+}
+
+func newPageTransport(next http.RoundTripper) *pageTransport {
+	return &pageTransport{next: next}
+}
+
+func (t *pageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	link, ok := req.Context().Value(pageKey{}).(string)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	target, err := req.URL.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("invalid next page link %q: %w", link, err)
+	}
+	if target.Scheme != req.URL.Scheme || target.Host != req.URL.Host {
+		return nil, fmt.Errorf("the next page link %q leaves %s", link, req.URL.Host)
+	}
+	req = req.Clone(req.Context())
+	req.URL = target
+	req.Host = ""
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,241 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNextLink(t *testing.T) {
+	headers := func(pairs ...string) *http.Response {
+		resp := &http.Response{Header: http.Header{}}
+		for i := 0; i+1 < len(pairs); i += 2 {
+			resp.Header.Add(pairs[i], pairs[i+1])
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name string
+		resp *http.Response
+		want string
+	}{
+		{name: "no response", resp: nil},
+		{name: "no headers", resp: headers()},
+		{name: "link to the next page", resp: headers("Link", `</assets?page=3&per_page=10>; rel="next"`), want: "/assets?page=3&per_page=10"},
+		{name: "next among other links", resp: headers("Link", `</assets?page=1>; rel="first", </assets?page=3>; rel="next"`), want: "/assets?page=3"},
+		{name: "next in a second link header", resp: headers("Link", `</assets?page=1>; rel="prev"`, "Link", `</assets?page=3>; rel="next"`), want: "/assets?page=3"},
+		{name: "link without next", resp: headers("Link", `</assets?page=1>; rel="prev", </assets?page=1>; rel="first"`)},
+		{name: "malformed link", resp: headers("Link", `/assets?page=3; rel="next"`)},
+		// a total alone says nothing about where the next page is
+		{name: "total only", resp: headers("X-Total-Count", "21")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextLink(tt.resp); got != tt.want {
+				t.Errorf("nextLink = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageLinks(t *testing.T) {
+	linkTo := func(target string) *http.Response {
+		resp := &http.Response{Header: http.Header{}}
+		if target != "" {
+			resp.Header.Set("Link", fmt.Sprintf(`<%s>; rel="next"`, target))
+		}
+		return resp
+	}
+
+	links := &pageLinks{}
+	ctx := context.Background()
+	if links.context(ctx) != ctx {
+		t.Error("the first page is not requested as the generated client builds it")
+	}
+	if !links.update(linkTo("/assets?cursor=b")) {
+		t.Fatal("update found no next page")
+	}
+	if got, _ := links.context(ctx).Value(pageKey{}).(string); got != "/assets?cursor=b" {
+		t.Errorf("next page = %q", got)
+	}
+	// a page linking back to itself would be read forever
+	if links.update(linkTo("/assets?cursor=b")) {
+		t.Error("a link to the page just read was followed")
+	}
+	if links.update(linkTo("")) {
+		t.Error("a page without a link has a next page")
+	}
+}
+
+func TestPageTransport(t *testing.T) {
+	var sent *http.Request
+	transport := newPageTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		sent = req
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}, nil
+	}))
+
+	tests := []struct {
+		name    string
+		link    string
+		want    string
+		wantErr bool
+	}{
+		{name: "no link", want: "https://api.example.com/api/v1/organizations?x=1"},
+		{name: "relative link", link: "/api/v1/organizations?cursor=b", want: "https://api.example.com/api/v1/organizations?cursor=b"},
+		{name: "absolute link", link: "https://api.example.com/api/v1/organizations?cursor=c", want: "https://api.example.com/api/v1/organizations?cursor=c"},
+		// the token must not follow a link anywhere else
+		{name: "other host", link: "https://evil.example.com/organizations?cursor=b", wantErr: true},
+		{name: "other scheme", link: "http://api.example.com/api/v1/organizations?cursor=b", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent = nil
+			links := &pageLinks{next: tt.link}
+			req, err := http.NewRequestWithContext(links.context(context.Background()), "GET", "https://api.example.com/api/v1/organizations?x=1", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = transport.RoundTrip(req)
+			if tt.wantErr {
+				if err == nil || sent != nil {
+					t.Errorf("followed %s", tt.link)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sent.URL.String(); got != tt.want {
+				t.Errorf("requested %s, want %s", got, tt.want)
+			}
+			// neither the page nor its size is ever made up by the client
+			if query := sent.URL.Query(); query.Has("page") || query.Has("per_page") {
+				t.Errorf("paging parameters were added: %s", sent.URL)
+			}
+		})
+	}
+}
+
+// pagesOf - a FetchPage over items that records the pages asked for
+func pagesOf(items []int, asked *[]Page) FetchPage[int] {
+	return func(ctx context.Context, page Page) ([]int, bool, error) {
+		*asked = append(*asked, page)
+		part, more := SlicePage(items, page)
+		return part, more, nil
+	}
+}
+
+func TestPager(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5, 6, 7}
+
+	tests := []struct {
+		name      string
+		fetch     func(asked *[]Page) FetchPage[int]
+		opts      PageOptions
+		want      []int
+		wantPages []Page
+		wantErr   error
+	}{
+		{
+			name:      "every page",
+			fetch:     func(asked *[]Page) FetchPage[int] { return pagesOf(items, asked) },
+			opts:      PageOptions{Size: 3},
+			want:      items,
+			wantPages: []Page{{1, 3}, {2, 3}, {3, 3}},
+		},
+		{
+			name:      "limit shrinks the page size",
+			fetch:     func(asked *[]Page) FetchPage[int] { return pagesOf(items, asked) },
+			opts:      PageOptions{Size: 3, Limit: 2},
+			want:      []int{1, 2},
+			wantPages: []Page{{1, 2}},
+		},
+		{
+			name:      "limit across pages",
+			fetch:     func(asked *[]Page) FetchPage[int] { return pagesOf(items, asked) },
+			opts:      PageOptions{Size: 3, Limit: 5},
+			want:      []int{1, 2, 3, 4, 5},
+			wantPages: []Page{{1, 3}, {2, 3}},
+		},
+		{
+			name: "one item pages",
+			fetch: func(asked *[]Page) FetchPage[int] {
+				return func(ctx context.Context, page Page) ([]int, bool, error) {
+					*asked = append(*asked, page)
+					// the api picks its page size, which need not be the one asked for
+					part, _ := SlicePage(items, Page{Number: page.Number, Size: 1})
+					return part, page.Number < len(items), nil
+				}
+			},
+			opts:      PageOptions{Size: 3},
+			want:      items,
+			wantPages: []Page{{1, 3}, {2, 3}, {3, 3}, {4, 3}, {5, 3}, {6, 3}, {7, 3}},
+		},
+		{
+			name: "identical pages",
+			fetch: func(asked *[]Page) FetchPage[int] {
+				return func(ctx context.Context, page Page) ([]int, bool, error) {
+					*asked = append(*asked, page)
+					// pages that look alike are still read, only a missing link ends the list
+					return []int{1}, page.Number < 3, nil
+				}
+			},
+			opts:      PageOptions{Size: 3},
+			want:      []int{1, 1, 1},
+			wantPages: []Page{{1, 3}, {2, 3}, {3, 3}},
+		},
+		{
+			name: "larger page than asked for",
+			fetch: func(asked *[]Page) FetchPage[int] {
+				return func(ctx context.Context, page Page) ([]int, bool, error) {
+					*asked = append(*asked, page)
+					if page.Number == 1 {
+						return items[:5], true, nil
+					}
+					return items[5:], false, nil
+				}
+			},
+			opts:      PageOptions{Size: 3},
+			want:      items,
+			wantPages: []Page{{1, 3}, {2, 3}},
+		},
+		{
+			name: "empty page stops",
+			fetch: func(asked *[]Page) FetchPage[int] {
+				return func(ctx context.Context, page Page) ([]int, bool, error) {
+					*asked = append(*asked, page)
+					return []int{}, true, nil
+				}
+			},
+			opts:      PageOptions{Size: 3},
+			want:      []int{},
+			wantPages: []Page{{1, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asked := []Page{}
+			got, err := Collect(context.Background(), NewPager(tt.fetch(&asked), tt.opts))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("items = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(asked, tt.wantPages) {
+				t.Errorf("pages = %v, want %v", asked, tt.wantPages)
+			}
+		})
+	}
+}
+
+// roundTripFunc - an http.RoundTripper that answers with a function
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
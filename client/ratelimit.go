@@ -0,0 +1,136 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimit - client-side token bucket shared by every request a client makes
+type RateLimit struct {
+	// RequestsPerSecond - sustained request rate, 0 disables the limiter
+	RequestsPerSecond float64
+	// Burst - how many requests may go out at once before throttling kicks in
+	Burst int
+}
+
+// DefaultRateLimit - the limit used when nothing is configured
+func DefaultRateLimit() RateLimit {
+	return RateLimit{
+		RequestsPerSecond: 10,
+		Burst:             10,
+	}
+}
+
+// rateLimiter - token bucket, tokens may go negative which queues callers in
+// the order they arrived
+type rateLimiter struct {
+	limit RateLimit
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &rateLimiter{
+		limit:  limit,
+		now:    time.Now,
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve - takes a token and returns how long the caller has to wait for it
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens += now.Sub(l.last).Seconds() * l.limit.RequestsPerSecond
+	if max := float64(l.limit.Burst); l.tokens > max {
+		l.tokens = max
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.limit.RequestsPerSecond * float64(time.Second))
+}
+
+// release - hands back a token the caller gave up waiting for
+func (l *rateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+// RequestStats - totals across every client in the process
+type RequestStats struct {
+	Requests  int64
+	Throttled time.Duration
+}
+
+var (
+	requestCount  int64
+	throttledTime int64
+)
+
+// Stats - requests sent so far, including retries, and the time spent
+// waiting on the rate limiter
+func Stats() RequestStats {
+	return RequestStats{
+		Requests:  atomic.LoadInt64(&requestCount),
+		Throttled: time.Duration(atomic.LoadInt64(&throttledTime)),
+	}
+}
+
+// Report - one line summary printed with --debug when a command exits
+func (s RequestStats) Report(w io.Writer) {
+	fmt.Fprintf(w, "--- %d request(s) made, %s spent throttled ---\n", s.Requests, s.Throttled.Round(time.Millisecond))
+}
+
+// rateLimitTransport - http.RoundTripper that waits for the limiter before
+// every attempt and counts the requests that go out
+type rateLimitTransport struct {
+	base    http.RoundTripper
+	limiter *rateLimiter
+}
+
+func newRateLimitTransport(base http.RoundTripper, limit RateLimit) *rateLimitTransport {
+	t := &rateLimitTransport{base: base}
+	if limit.RequestsPerSecond > 0 {
+		t.limiter = newRateLimiter(limit)
+	}
+	return t
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.limiter != nil {
+		if wait := t.limiter.reserve(); wait > 0 {
+			timer := time.NewTimer(wait)
+			start := time.Now()
+			select {
+			case <-timer.C:
+			case <-req.Context().Done():
+				timer.Stop()
+				t.limiter.release()
+				atomic.AddInt64(&throttledTime, int64(time.Since(start)))
+				return nil, req.Context().Err()
+			}
+			atomic.AddInt64(&throttledTime, int64(time.Since(start)))
+		}
+	}
+
+	atomic.AddInt64(&requestCount, 1)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,168 @@
+package client
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy - controls how transient cloud api failures are retried
+type RetryPolicy struct {
+	// MaxAttempts - total number of tries per request, 1 disables retries
+	MaxAttempts int
+	// BaseDelay - delay before the first retry, doubled on every attempt
+	BaseDelay time.Duration
+	// MaxDelay - upper bound for a single backoff delay
+	MaxDelay time.Duration
+	// RetryNonIdempotent - also replay verbs like POST which are not safe to repeat
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy - the policy used when nothing is configured
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// attemptFn - called after every attempt out of the attempts the request
+// gets, resp is nil when err is set
+type attemptFn func(attempt int, attempts int, resp *http.Response, err error)
+
+// retryTransport - http.RoundTripper that replays requests that failed with a transient error
+type retryTransport struct {
+	base      http.RoundTripper
+	policy    RetryPolicy
+	onAttempt attemptFn
+}
+
+func newRetryTransport(base http.RoundTripper, policy RetryPolicy, onAttempt attemptFn) *retryTransport {
+	return &retryTransport{base: base, policy: policy, onAttempt: onAttempt}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfter - parses the Retry-After header which is either seconds or an http date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		delay := time.Until(at)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// backoff - exponential backoff with full jitter, Retry-After wins when the
+// server sends it.  A Retry-After longer than MaxDelay is not waited out, ok
+// is false and the response is handed back as it is.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) (time.Duration, bool) {
+	if delay, ok := retryAfter(resp); ok {
+		return delay, delay <= p.MaxDelay
+	}
+
+	ceiling := p.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0, true
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1)), true
+}
+
+func (t *retryTransport) maxAttempts(req *http.Request) int {
+	if t.policy.MaxAttempts < 1 {
+		return 1
+	}
+	if !t.policy.RetryNonIdempotent && !isIdempotent(req.Method) {
+		return 1
+	}
+	// a body we cannot rewind cannot be replayed
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return 1
+	}
+	return t.policy.MaxAttempts
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attempts := t.maxAttempts(req)
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req
+		if attempt > 1 {
+			attemptReq = req.Clone(ctx)
+			if req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				attemptReq.Body = body
+			}
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if t.onAttempt != nil {
+			t.onAttempt(attempt, attempts, resp, err)
+		}
+
+		retryable := (err != nil && ctx.Err() == nil) || (err == nil && isRetryableStatus(resp.StatusCode))
+		if attempt >= attempts || !retryable {
+			return resp, err
+		}
+
+		delay, ok := t.policy.backoff(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil {
+			// drain so the connection can be reused for the next attempt
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
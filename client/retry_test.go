@@ -0,0 +1,123 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+	tests := []struct {
+		name   string
+		method string
+		policy RetryPolicy
+		// statuses - what the server answers, the last one repeats
+		statuses   []int
+		retryAfter string
+		want       int
+		wantCalls  int32
+		// wantAttempts - the attempts reported to onAttempt
+		wantAttempts int
+	}{
+		{name: "succeeds after transient failures", method: "GET", policy: policy, statuses: []int{503, 502, 200}, want: 200, wantCalls: 3, wantAttempts: 3},
+		{name: "gives up after max attempts", method: "GET", policy: policy, statuses: []int{503}, want: 503, wantCalls: 3, wantAttempts: 3},
+		{name: "client errors are not retried", method: "GET", policy: policy, statuses: []int{404}, want: 404, wantCalls: 1, wantAttempts: 3},
+		{name: "post is not replayed", method: "POST", policy: policy, statuses: []int{503, 200}, want: 503, wantCalls: 1, wantAttempts: 1},
+		{
+			name:     "post is replayed when allowed",
+			method:   "POST",
+			policy:   RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond, RetryNonIdempotent: true},
+			statuses: []int{503, 200}, want: 200, wantCalls: 2, wantAttempts: 3,
+		},
+		{name: "one attempt disables retries", method: "GET", policy: RetryPolicy{MaxAttempts: 1}, statuses: []int{503, 200}, want: 503, wantCalls: 1, wantAttempts: 1},
+		{name: "short retry-after is waited out", method: "GET", policy: policy, statuses: []int{429, 200}, retryAfter: "0", want: 200, wantCalls: 2, wantAttempts: 3},
+		{name: "long retry-after is handed back", method: "GET", policy: policy, statuses: []int{429, 200}, retryAfter: "3600", want: 429, wantCalls: 1, wantAttempts: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&calls, 1))
+				if n > len(tt.statuses) {
+					n = len(tt.statuses)
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.statuses[n-1])
+			}))
+			defer server.Close()
+
+			var gotAttempts []int
+			transport := newRetryTransport(http.DefaultTransport, tt.policy, func(attempt, attempts int, resp *http.Response, err error) {
+				gotAttempts = append(gotAttempts, attempts)
+			})
+			req, err := http.NewRequest(tt.method, server.URL, strings.NewReader("{}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("server saw %d requests, want %d", calls, tt.wantCalls)
+			}
+			// the attempts reported are the ones the request really gets
+			if len(gotAttempts) != int(tt.wantCalls) {
+				t.Errorf("onAttempt called %d times, want %d", len(gotAttempts), tt.wantCalls)
+			}
+			for _, attempts := range gotAttempts {
+				if attempts != tt.wantAttempts {
+					t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	withRetryAfter := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+	}
+
+	tests := []struct {
+		name    string
+		attempt int
+		resp    *http.Response
+		min     time.Duration
+		max     time.Duration
+		wantOk  bool
+	}{
+		{name: "first retry", attempt: 1, max: 100 * time.Millisecond, wantOk: true},
+		{name: "doubles", attempt: 3, max: 400 * time.Millisecond, wantOk: true},
+		{name: "capped at max delay", attempt: 10, max: time.Second, wantOk: true},
+		{name: "shift overflow is capped", attempt: 80, max: time.Second, wantOk: true},
+		{name: "retry-after wins", attempt: 1, resp: withRetryAfter("1"), min: time.Second, max: time.Second, wantOk: true},
+		{name: "retry-after beyond max delay", attempt: 1, resp: withRetryAfter("2"), min: 2 * time.Second, max: 2 * time.Second, wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				delay, ok := policy.backoff(tt.attempt, tt.resp)
+				if ok != tt.wantOk {
+					t.Fatalf("ok = %v, want %v", ok, tt.wantOk)
+				}
+				if delay < tt.min || delay > tt.max {
+					t.Fatalf("delay = %s, want between %s and %s", delay, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
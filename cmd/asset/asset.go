@@ -1,16 +1,21 @@
 package asset
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	cac "github.com/aptible/cloud-api-clients/clients/go"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/aptible/cloud-cli/client"
 	"github.com/aptible/cloud-cli/config"
 	"github.com/aptible/cloud-cli/lib/asset"
 	libenv "github.com/aptible/cloud-cli/lib/env"
+	libkv "github.com/aptible/cloud-cli/lib/kv"
+	libop "github.com/aptible/cloud-cli/lib/op"
 	"github.com/aptible/cloud-cli/ui/asset"
 	"github.com/aptible/cloud-cli/ui/fetch"
 	"github.com/aptible/cloud-cli/ui/form"
@@ -23,28 +28,51 @@ type AssetOptions struct {
 	VpcName       string
 	Engine        string
 	EngineVersion string
+	AllEnvs       bool
+	Concurrency   int
+	Params        []string
+	Page          client.PageOptions
 }
 
 var assetOptions = AssetOptions{}
 
+// assetArg - the asset named by the positional argument or --asset
+func assetArg(args []string) (string, error) {
+	if len(args) == 0 {
+		return assetOptions.Asset, nil
+	}
+	if assetOptions.Asset != "" && assetOptions.Asset != args[0] {
+		return "", fmt.Errorf("pass the asset as an argument or with --asset, not both")
+	}
+	return args[0], nil
+}
+
 // describeAsset - aliased func but describes any given asset by its asset id, env id (rds/vpc for example use this)
 func describeAsset() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		ref, err := assetArg(args)
+		if err != nil {
+			return err
+		}
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 
 		formResult := form.FormResult{
 			Org:   config.Vconfig.GetString("org"),
 			Env:   config.Vconfig.GetString("env"),
-			Asset: assetOptions.Asset,
+			Asset: ref,
 		}
-		err := libasset.AssetDescribeForm(config, &formResult)
+		err = libasset.AssetDescribeForm(config, &formResult)
 		if err != nil {
-			return nil
+			return err
 		}
 
 		msg := fmt.Sprintf("describing asset %s", formResult.Asset)
-		model := fetch.NewModel(msg, func() (interface{}, error) {
-			return config.Cc.DescribeAsset(formResult.Org, formResult.Env, formResult.Asset)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return config.Cc.DescribeAsset(ctx, formResult.Org, formResult.Env, formResult.Asset)
 		})
 		data, err := fetch.WithOutput(model)
 		if err != nil {
@@ -64,19 +92,23 @@ func assetDescribeRun() config.CobraRunE {
 
 func assetBundleRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 		formResult := form.FormResult{
 			Org: config.Vconfig.GetString("org"),
 			Env: config.Vconfig.GetString("env"),
 		}
-		err := libenv.EnvForm(config, &formResult)
+		err = libenv.EnvForm(config, &formResult)
 		if err != nil {
-			return nil
+			return err
 		}
 
 		msg := fmt.Sprintf("fetching available asset bundles for environment %s", formResult.Env)
-		model := fetch.NewModel(msg, func() (interface{}, error) {
-			return config.Cc.ListAssetBundles(formResult.Org, formResult.Env)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return config.Cc.ListAssetBundles(ctx, formResult.Org, formResult.Env)
 		})
 		result, err := fetch.WithOutput(model)
 		if err != nil {
@@ -90,24 +122,33 @@ func assetBundleRun() config.CobraRunE {
 	}
 }
 
-// destroyAsset - aliased func but also can destroy assets on top level (rds/vpc for example use this)
-func destroyAsset() config.CobraRunE {
+// destroyAsset - aliased func but also can destroy assets on top level (rds/vpc for example use this),
+// only assets of types are matched so e.g. `network destroy` never removes a datastore
+func destroyAsset(kind string, types []string) config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		ref, err := assetArg(args)
+		if err != nil {
+			return err
+		}
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 
 		formResult := form.FormResult{
 			Org:   config.Vconfig.GetString("org"),
 			Env:   config.Vconfig.GetString("env"),
-			Asset: assetOptions.Asset,
+			Asset: ref,
 		}
-		err := libasset.AssetDescribeForm(config, &formResult)
+		err = libasset.AssetDestroyForm(kind, types)(config, &formResult)
 		if err != nil {
-			return nil
+			return err
 		}
 
 		msg := fmt.Sprintf("destroying asset %s", formResult.Asset)
-		model := fetch.NewModel(msg, func() (interface{}, error) {
-			err := config.Cc.DestroyAsset(formResult.Org, formResult.Env, formResult.Asset)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			err := config.Cc.DestroyAsset(ctx, formResult.Org, formResult.Env, formResult.Asset)
 			return nil, err
 		})
 		_, err = fetch.WithOutput(model)
@@ -123,7 +164,11 @@ func destroyAsset() config.CobraRunE {
 // assetsCreateRun - create an asset
 func assetsCreateRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 		org := config.Vconfig.GetString("org")
 		env := config.Vconfig.GetString("env")
 
@@ -135,7 +180,7 @@ func assetsCreateRun() config.CobraRunE {
 			Engine:        assetOptions.Engine,
 			EngineVersion: assetOptions.EngineVersion,
 		}
-		err := libasset.AssetCreateForm(config, &formResult)
+		err = libasset.AssetCreateForm(config, &formResult)
 		if err != nil {
 			return err
 		}
@@ -155,8 +200,8 @@ func assetsCreateRun() config.CobraRunE {
 		}
 
 		msg := fmt.Sprintf("creating asset %s (v%s)", formResult.Engine, formResult.EngineVersion)
-		model := fetch.NewModel(msg, func() (interface{}, error) {
-			return config.Cc.CreateAsset(formResult.Org, formResult.Env, params)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return config.Cc.CreateAsset(ctx, formResult.Org, formResult.Env, params)
 		})
 
 		result, err := fetch.WithOutput(model)
@@ -176,51 +221,167 @@ func assetsCreateRun() config.CobraRunE {
 	}
 }
 
+// assetsUpdateRun - change the parameters of an existing asset
+func assetsUpdateRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		ref, err := assetArg(args)
+		if err != nil {
+			return err
+		}
+		overrides, err := libkv.Parse(assetOptions.Params)
+		if err != nil {
+			return err
+		}
+
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+		formResult := form.FormResult{
+			Org:   config.Vconfig.GetString("org"),
+			Env:   config.Vconfig.GetString("env"),
+			Asset: ref,
+		}
+		err = libasset.AssetDescribeForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("describing asset %s", formResult.Asset)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return config.Cc.DescribeAsset(ctx, formResult.Org, formResult.Env, formResult.Asset)
+		})
+		data, err := fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+		asset := data.Result.(*cac.AssetOutput)
+		current := asset.CurrentAssetParameters.Data
+
+		// --param skips the prompts so the command can be scripted
+		if len(overrides) == 0 {
+			overrides, err = libasset.AssetParamsForm(config, current)
+			if err != nil {
+				return err
+			}
+		}
+
+		updated, err := libasset.ApplyParams(current, overrides)
+		if err != nil {
+			return err
+		}
+		diff := libasset.ParamDiff(current, updated)
+		if len(diff) == 0 {
+			fmt.Println("No changes to apply.")
+			return nil
+		}
+		fmt.Printf("Changes to asset %s:\n", formResult.Asset)
+		for _, line := range diff {
+			fmt.Printf("  %s\n", line)
+		}
+
+		params := cac.AssetInput{
+			Asset:           asset.Asset,
+			AssetVersion:    asset.AssetVersion,
+			AssetParameters: updated,
+		}
+		msg = fmt.Sprintf("updating asset %s", formResult.Asset)
+		model = fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			_, opId, err := config.Cc.UpdateAsset(ctx, formResult.Org, formResult.Env, formResult.Asset, params)
+			return opId, err
+		})
+		data, err = fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+
+		// without the operation from the response there is no telling which
+		// of the asset's operations is this update's
+		opId := data.Result.(string)
+		if opId == "" {
+			fmt.Printf("Asset is being updated.\nTo see its progress, run:\n")
+			fmt.Printf(
+				"	aptible asset show --org %s --env %s --asset %s\n",
+				formResult.Org,
+				formResult.Env,
+				formResult.Asset,
+			)
+			return nil
+		}
+
+		msg = fmt.Sprintf("waiting for operation %s", opId)
+		model = fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return libop.WaitForOperation(ctx, config.Cc, formResult.Org, formResult.Asset, opId, 3*time.Second)
+		})
+		data, err = fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+
+		op := data.Result.(*cac.OperationOutput)
+		fmt.Println(libop.OpTable(op).View())
+		if status := libop.StatusName(*op); status != string(cac.OPERATIONSTATUS_COMPLETE) {
+			return fmt.Errorf("operation %s finished with status %s", op.Id, status)
+		}
+		return nil
+	}
+}
+
 // assetsDestroyRun - destory an asset
 func assetsDestroyRun() config.CobraRunE {
-	return destroyAsset()
+	return destroyAsset("asset", nil)
 }
 
 // assetsListRun - list all possible assets with config fields
 func assetsListRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		if assetOptions.AllEnvs {
+			// the fan-out lists each environment in full so the table can
+			// be grouped by environment, paging does not apply to it and
+			// neither does picking one environment
+			for _, name := range []string{"env", "limit", "page-size"} {
+				if cmd.Flags().Changed(name) {
+					return fmt.Errorf("--%s cannot be combined with --all-envs", name)
+				}
+			}
+			return inventoryRun()(cmd, args)
+		}
+		if err := assetOptions.Page.Validate(); err != nil {
+			return err
+		}
+
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 		org := config.Vconfig.GetString("org")
 		env := config.Vconfig.GetString("env")
 
 		formResult := form.FormResult{Org: org, Env: env}
-		err := libenv.EnvForm(config, &formResult)
+		err = libenv.EnvForm(config, &formResult)
 		if err != nil {
 			return err
 		}
 
 		msg := fmt.Sprintf("getting assets with %+v", formResult)
-		model := fetch.NewModel(msg, func() (interface{}, error) {
-			return config.Cc.ListAssets(formResult.Org, formResult.Env)
+		pager := config.Cc.AssetPages(formResult.Org, formResult.Env, assetOptions.Page)
+		count, err := fetch.Pages(config.Ctx, msg, pager, func(assets []cac.AssetOutput, first bool) {
+			// TODO - print with tea
+			if first {
+				fmt.Println("Asset(s) List")
+			}
+			fmt.Println(libasset.AssetTable(assets).WithHeaderVisibility(first).View())
 		})
-
-		rawResult, err := fetch.WithOutput(model)
 		if err != nil {
 			return err
 		}
-		if rawResult == nil {
-			// TODO - print with tea
-			fmt.Println("No datastores found.")
-			return nil
-		}
-
-		results := rawResult.Result.([]cac.AssetOutput)
-		if len(results) == 0 {
+		if count == 0 {
 			// TODO - print with tea
 			fmt.Println("No assets found.")
-			return nil
 		}
 
-		dsTable := libasset.AssetTable(results)
-		// TODO - print with tea
-		fmt.Println("Asset(s) List")
-		fmt.Println(dsTable.View())
-
 		return nil
 	}
 }
@@ -243,13 +404,23 @@ func NewAssetCmd() *cobra.Command {
 	}
 
 	assetDestroyCmd := &cobra.Command{
-		Use:     "destroy [asset_id]",
+		Use:     "destroy [asset_id|asset_name]",
 		Short:   "permanently remove the asset.",
 		Long:    `The asset destroy command will permanently remove the asset.`,
 		Aliases: []string{"d", "delete", "rm", "remove"},
+		Args:    cobra.MaximumNArgs(1),
 		RunE:    assetsDestroyRun(),
 	}
 
+	assetUpdateCmd := &cobra.Command{
+		Use:     "update [asset_id|asset_name]",
+		Short:   "change the parameters of an asset.",
+		Long:    `The asset update command changes the parameters of an existing asset and waits for the resulting operation to finish.`,
+		Aliases: []string{"u", "edit"},
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    assetsUpdateRun(),
+	}
+
 	assetListCmd := &cobra.Command{
 		Use:     "list",
 		Short:   "list all assets within an organization.",
@@ -259,10 +430,11 @@ func NewAssetCmd() *cobra.Command {
 	}
 
 	assetDescribeCmd := &cobra.Command{
-		Use:     "describe",
+		Use:     "describe [asset_id|asset_name]",
 		Short:   "Show asset detail",
 		Long:    `The assets describe command will provide more detail about the asset`,
 		Aliases: []string{"show"},
+		Args:    cobra.MaximumNArgs(1),
 		RunE:    assetDescribeRun(),
 	}
 
@@ -281,10 +453,19 @@ func NewAssetCmd() *cobra.Command {
 	assetCreateCmd.Flags().StringVarP(&assetOptions.EngineVersion, "engine-version", "", "", "engine version")
 	assetCreateCmd.Flags().StringVarP(&assetOptions.Asset, "asset", "", "", "asset id")
 
-	assetDescribeCmd.Flags().StringVarP(&assetOptions.Asset, "asset", "", "", "asset id")
+	assetUpdateCmd.Flags().StringVarP(&assetOptions.Asset, "asset", "", "", "asset id or name")
+	assetUpdateCmd.Flags().StringArrayVarP(&assetOptions.Params, "param", "", []string{}, "parameter to change as key=value, can be repeated")
+
+	assetListCmd.Flags().BoolVarP(&assetOptions.AllEnvs, "all-envs", "", false, "list assets across every environment in the organization")
+	assetListCmd.Flags().IntVarP(&assetOptions.Concurrency, "concurrency", "", defaultConcurrency, "how many environments to query at once with --all-envs")
+	config.AddPageFlags(assetListCmd, &assetOptions.Page)
+
+	assetDescribeCmd.Flags().StringVarP(&assetOptions.Asset, "asset", "", "", "asset id or name")
+	assetDestroyCmd.Flags().StringVarP(&assetOptions.Asset, "asset", "", "", "asset id or name")
 
 	assetCmd.AddCommand(assetCreateCmd)
 	assetCmd.AddCommand(assetDestroyCmd)
+	assetCmd.AddCommand(assetUpdateCmd)
 	assetCmd.AddCommand(assetListCmd)
 	assetCmd.AddCommand(assetDescribeCmd)
 	assetCmd.AddCommand(assetBundleCmd)
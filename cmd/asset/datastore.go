@@ -1,6 +1,7 @@
 package asset
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -33,25 +34,29 @@ func dsDescribeRun() config.CobraRunE {
 
 // dsDestroyRun - destroy datastore
 func dsDestroyRun() config.CobraRunE {
-	return destroyAsset()
+	return destroyAsset("datastore", []string{"rds"})
 }
 
 // dsListRun - list datastores
 func dsListRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 		org := config.Vconfig.GetString("org")
 		env := config.Vconfig.GetString("env")
 
 		formResult := form.FormResult{Org: org, Env: env}
-		err := libenv.EnvForm(config, &formResult)
+		err = libenv.EnvForm(config, &formResult)
 		if err != nil {
-			return nil
+			return err
 		}
 
 		msg := fmt.Sprintf("geting datastores with %+v", formResult)
-		model := fetch.NewModel(msg, func() (interface{}, error) {
-			return config.Cc.ListAssets(formResult.Org, formResult.Env)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return config.Cc.ListAssets(ctx, formResult.Org, formResult.Env)
 		})
 
 		rawResult, err := fetch.WithOutput(model)
@@ -106,11 +111,11 @@ func NewDatastoreCmd() *cobra.Command {
 	}
 
 	dsDestroyCmd := &cobra.Command{
-		Use:     "destroy [datastore_id]",
+		Use:     "destroy [datastore_id|datastore_name]",
 		Short:   "permanently remove the datastore.",
 		Long:    `The datastore destroy command will permanently remove the datastore.`,
 		Aliases: []string{"d", "delete", "rm", "remove"},
-		Args:    cobra.MinimumNArgs(1),
+		Args:    cobra.MaximumNArgs(1),
 		RunE:    dsDestroyRun(),
 	}
 
@@ -123,10 +128,11 @@ func NewDatastoreCmd() *cobra.Command {
 	}
 
 	dsDescribeCmd := &cobra.Command{
-		Use:     "describe",
+		Use:     "describe [datastore_id|datastore_name]",
 		Short:   "describe datastore",
 		Long:    `The datastore show command will provide more detail about a datastore`,
 		Aliases: []string{"show"},
+		Args:    cobra.MaximumNArgs(1),
 		RunE:    dsDescribeRun(),
 	}
 
@@ -0,0 +1,98 @@
+package asset
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/aptible/cloud-cli/client"
+	"github.com/aptible/cloud-cli/config"
+	libasset "github.com/aptible/cloud-cli/lib/asset"
+	liborg "github.com/aptible/cloud-cli/lib/org"
+	"github.com/aptible/cloud-cli/ui/fetch"
+	"github.com/aptible/cloud-cli/ui/form"
+)
+
+const defaultConcurrency = 4
+
+// inventoryRun - lists the assets of every environment within an organization
+func inventoryRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("env") {
+			return fmt.Errorf("--env cannot be combined with inventory, which lists every environment")
+		}
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+
+		formResult := form.FormResult{Org: config.Vconfig.GetString("org")}
+		err = liborg.OrgForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("getting assets for every environment in %s", formResult.Org)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			envs, err := config.Cc.ListEnvironments(ctx, formResult.Org)
+			if err != nil {
+				return nil, err
+			}
+			return libasset.Inventory(ctx, config.Cc, formResult.Org, envs, assetOptions.Concurrency), nil
+		})
+
+		rawResult, err := fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+
+		results := rawResult.Result.([]libasset.EnvAssets)
+		total := 0
+		for _, result := range results {
+			total += len(result.Assets)
+		}
+		if total == 0 {
+			// TODO - print with tea
+			fmt.Println("No assets found.")
+		} else {
+			invTable := libasset.InventoryTable(results)
+			// TODO - print with tea
+			fmt.Println("Asset Inventory")
+			fmt.Println(invTable.View())
+		}
+
+		for _, result := range results {
+			if result.Err != nil {
+				fmt.Fprintf(
+					os.Stderr,
+					"Unable to list assets for environment %s (%s): %s\n",
+					result.Env.Name,
+					result.Env.Id,
+					client.RenderError(result.Err),
+				)
+			}
+		}
+
+		return libasset.InventoryErr(results)
+	}
+}
+
+// NewInventoryCmd - lists assets across every environment of an organization
+func NewInventoryCmd() *cobra.Command {
+	inventoryCmd := &cobra.Command{
+		Use:     "inventory",
+		Short:   "list every asset across all environments of an organization.",
+		Long:    `The inventory command lists the assets of every environment within an organization, environments that cannot be listed are reported without stopping the command.`,
+		Aliases: []string{"inv"},
+		Args:    cobra.NoArgs,
+		RunE:    inventoryRun(),
+	}
+
+	inventoryCmd.Flags().IntVarP(&assetOptions.Concurrency, "concurrency", "", defaultConcurrency, "how many environments to query at once")
+
+	return inventoryCmd
+}
@@ -1,6 +1,7 @@
 package asset
 
 import (
+	"context"
 	"fmt"
 
 	cac "github.com/aptible/cloud-api-clients/clients/go"
@@ -16,14 +17,18 @@ import (
 // dsCreateRun - create a datastore
 func vpcCreateRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 		org := config.Vconfig.GetString("org")
 		env := config.Vconfig.GetString("env")
 
 		formResult := form.FormResult{Org: org, Env: env}
-		err := libenv.EnvForm(config, &formResult)
+		err = libenv.EnvForm(config, &formResult)
 		if err != nil {
-			return nil
+			return err
 		}
 
 		name := args[0]
@@ -38,8 +43,8 @@ func vpcCreateRun() config.CobraRunE {
 		}
 
 		msg := fmt.Sprintf("creating vpc (%s)", name)
-		model := fetch.NewModel(msg, func() (interface{}, error) {
-			return config.Cc.CreateAsset(formResult.Org, formResult.Env, params)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return config.Cc.CreateAsset(ctx, formResult.Org, formResult.Env, params)
 		})
 
 		result, err := fetch.WithOutput(model)
@@ -62,25 +67,29 @@ func vpcDescribeRun() config.CobraRunE {
 
 // dsDestroyRun - destroy datastore
 func vpcDestroyRun() config.CobraRunE {
-	return destroyAsset()
+	return destroyAsset("network", []string{"vpc"})
 }
 
 // vpcListRun - list vpcs
 func vpcListRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 		org := config.Vconfig.GetString("org")
 		env := config.Vconfig.GetString("env")
 
 		formResult := form.FormResult{Org: org, Env: env}
-		err := libenv.EnvForm(config, &formResult)
+		err = libenv.EnvForm(config, &formResult)
 		if err != nil {
-			return nil
+			return err
 		}
 
 		msg := fmt.Sprintf("getting vpcs with %+v", formResult)
-		model := fetch.NewModel(msg, func() (interface{}, error) {
-			return config.Cc.ListAssets(formResult.Org, formResult.Env)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return config.Cc.ListAssets(ctx, formResult.Org, formResult.Env)
 		})
 
 		rawResult, err := fetch.WithOutput(model)
@@ -126,11 +135,11 @@ func NewVPCCmd() *cobra.Command {
 	}
 
 	vpcDestroyCmd := &cobra.Command{
-		Use:     "destroy [asset_id]",
+		Use:     "destroy [asset_id|asset_name]",
 		Short:   "permanently remove the network.",
 		Long:    `The network destroy command will permanently remove the network.`,
 		Aliases: []string{"d", "delete", "rm", "remove"},
-		Args:    cobra.ExactArgs(1),
+		Args:    cobra.MaximumNArgs(1),
 		RunE:    vpcDestroyRun(),
 	}
 
@@ -143,10 +152,11 @@ func NewVPCCmd() *cobra.Command {
 	}
 
 	vpcDescribeCmd := &cobra.Command{
-		Use:     "describe [asset_id]",
+		Use:     "describe [asset_id|asset_name]",
 		Short:   "describe vpc",
 		Long:    `The network describe command will provide more detail for a network`,
 		Aliases: []string{"show"},
+		Args:    cobra.MaximumNArgs(1),
 		RunE:    vpcDescribeRun(),
 	}
 
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/aptible/cloud-cli/client"
+	"github.com/aptible/cloud-cli/config"
+	libauth "github.com/aptible/cloud-cli/lib/auth"
+	libop "github.com/aptible/cloud-cli/lib/op"
+	"github.com/aptible/cloud-cli/ui/common"
+)
+
+type LoginOptions struct {
+	Email    string
+	OTP      string
+	Lifetime time.Duration
+}
+
+var loginOptions = LoginOptions{}
+
+// loginRun - exchanges an email and password for a token and keeps it in the credential store
+func loginRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		v := viper.GetViper()
+		// opened first so a broken store fails before the password is asked for
+		store, err := config.OpenCredentialStore(v)
+		if err != nil {
+			return err
+		}
+
+		network := config.Network(v)
+		if _, err := network.Transport(); err != nil {
+			return fmt.Errorf("invalid network settings: %w", err)
+		}
+		auth := client.NewAuthClient(v.GetBool("debug"), v.GetString("auth-domain"), client.WithNetwork(network))
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if timeout := v.GetDuration("timeout"); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		creds := client.Credentials{
+			Email:    loginOptions.Email,
+			OTP:      loginOptions.OTP,
+			Lifetime: loginOptions.Lifetime,
+		}
+		if creds.Email == "" {
+			creds.Email, err = libauth.Prompt(libauth.NewEmailProp())
+			if err != nil {
+				return err
+			}
+		}
+		creds.Password, err = libauth.Prompt(libauth.NewPasswordProp())
+		if err != nil {
+			return err
+		}
+
+		token, err := auth.CreateToken(ctx, creds)
+		if errors.Is(err, client.ErrOTPRequired) && creds.OTP == "" {
+			creds.OTP, err = libauth.Prompt(libauth.NewOTPProp())
+			if err != nil {
+				return err
+			}
+			token, err = auth.CreateToken(ctx, creds)
+		}
+		if err != nil {
+			return err
+		}
+
+		domain := config.AuthURL(v)
+		if err := store.Set(domain, token.AccessToken); err != nil {
+			return err
+		}
+
+		expiresIn := time.Duration(token.ExpiresIn) * time.Second
+		fmt.Printf("Logged in to %s as %s, the token expires in %s.\n", domain, creds.Email, config.FormatDuration(expiresIn))
+		return nil
+	}
+}
+
+// logoutRun - removes the token for the auth domain from the credential store
+// and the credential helper's cache
+func logoutRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		v := viper.GetViper()
+		store, err := config.OpenCredentialStore(v)
+		if err != nil {
+			return err
+		}
+
+		domain := config.AuthURL(v)
+		found, err := store.Delete(domain)
+		if err != nil {
+			return err
+		}
+		if command := v.GetString("credential-helper"); command != "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			// the helper is asked for a fresh token next time
+			forgot, err := config.NewCredentialHelper(command, config.HelperCacheStore(v, home)).Forget(domain)
+			if err != nil {
+				return err
+			}
+			found = found || forgot
+		}
+		if !found {
+			fmt.Printf("Not logged in to %s.\n", domain)
+			return nil
+		}
+
+		fmt.Printf("Logged out of %s.\n", domain)
+		return nil
+	}
+}
+
+// authStatusRun - what the token says about itself, the api is not asked
+func authStatusRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		v := viper.GetViper()
+		domain := config.AuthURL(v)
+		token, source, err := config.Token(cmd.Context(), v)
+		if err != nil {
+			return err
+		}
+		if token == "" {
+			return &client.APIError{
+				Kind:    client.KindUnauthorized,
+				Message: fmt.Sprintf("not logged in to %s, run `aptible login`", domain),
+			}
+		}
+
+		pairs := []string{"Auth domain", domain, "Source", source}
+		claims, err := client.ParseClaims(token)
+		if err != nil {
+			pairs = append(pairs, "Token", "not a JWT, only the api can tell whether it is valid")
+			fmt.Println(common.KeyValueView(pairs...))
+			return nil
+		}
+
+		now := time.Now()
+		expires := "never"
+		switch {
+		case claims.Expired(now):
+			expires = fmt.Sprintf("%s (expired %s ago)", libop.FormatTime(claims.ExpiresAt), config.FormatDuration(-claims.ExpiresIn(now)))
+		case claims.Expires():
+			expires = fmt.Sprintf("%s (in %s)", libop.FormatTime(claims.ExpiresAt), config.FormatDuration(claims.ExpiresIn(now)))
+		}
+		pairs = append(pairs,
+			"Subject", claims.Subject,
+			"Email", claims.Email,
+			"Issuer", claims.Issuer,
+			"Scope", claims.Scope,
+			"Issued", libop.FormatTime(claims.IssuedAt),
+			"Expires", expires,
+		)
+		fmt.Println(common.KeyValueView(pairs...))
+
+		// scripts can check the exit code before running anything else
+		return config.CheckTokenExpiry(io.Discard, v, token, now)
+	}
+}
+
+// NewAuthCmd - inspect the credentials the cli uses
+func NewAuthCmd() *cobra.Command {
+	authCmd := &cobra.Command{
+		Use:   "auth",
+		Short: "The auth subcommand inspects the credentials the cli uses.",
+		Long:  `The auth subcommand inspects the credentials the cli uses, see login and logout to change them.`,
+	}
+
+	authStatusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "show who the token belongs to and when it expires.",
+		Long:  `The auth status command decodes the token locally and shows its subject, issuer, auth domain and time to expiry.  It exits with 4 when there is no token or it has expired.`,
+		Args:  cobra.NoArgs,
+		RunE:  authStatusRun(),
+	}
+
+	authCmd.AddCommand(authStatusCmd)
+
+	return authCmd
+}
+
+// NewLoginCmd - log in to the auth domain
+func NewLoginCmd() *cobra.Command {
+	loginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "log in to Aptible.",
+		Long:  `The login command asks for your email, password and, when two-factor authentication is enabled, a one-time password.  The token is kept in the credential store for the --auth-domain.`,
+		Args:  cobra.NoArgs,
+		RunE:  loginRun(),
+	}
+
+	loginCmd.Flags().StringVarP(&loginOptions.Email, "email", "", "", "email to log in with, prompted for when empty")
+	loginCmd.Flags().StringVarP(&loginOptions.OTP, "otp", "", "", "one-time password, prompted for when the account requires one")
+	loginCmd.Flags().DurationVarP(&loginOptions.Lifetime, "lifetime", "", 12*time.Hour, "how long the token stays valid, the auth server may shorten it")
+
+	return loginCmd
+}
+
+// NewLogoutCmd - forget the token for the auth domain
+func NewLogoutCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "log out of Aptible.",
+		Long:  `The logout command removes the token for the --auth-domain from the credential store.`,
+		Args:  cobra.NoArgs,
+		RunE:  logoutRun(),
+	}
+}
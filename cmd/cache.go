@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aptible/cloud-cli/client/cache"
+	"github.com/aptible/cloud-cli/config"
+)
+
+// cacheClearRun - removes every cached api lookup
+func cacheClearRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+
+		dir := config.CacheDir(home)
+		if err := cache.Clear(dir); err != nil {
+			return err
+		}
+
+		fmt.Printf("Cleared cache: %s\n", dir)
+		return nil
+	}
+}
+
+// NewCacheCmd - manage the local cache of api lookups
+func NewCacheCmd() *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "The cache subcommand manages the local cache of api lookups.",
+		Long:  `The cache subcommand manages the local cache of api lookups used by interactive prompts, stored in ~/.aptible/cache.`,
+	}
+
+	cacheClearCmd := &cobra.Command{
+		Use:     "clear",
+		Short:   "remove every cached api lookup.",
+		Long:    `The cache clear command removes every cached api lookup, the next command will fetch fresh data from the api.`,
+		Aliases: []string{"rm", "purge"},
+		Args:    cobra.NoArgs,
+		RunE:    cacheClearRun(),
+	}
+
+	cacheCmd.AddCommand(cacheClearCmd)
+
+	return cacheCmd
+}
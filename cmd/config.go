@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/aptible/cloud-cli/config"
+	libconfig "github.com/aptible/cloud-cli/lib/config"
+)
+
+type ConfigOptions struct {
+	Force bool
+}
+
+var configOptions = ConfigOptions{}
+
+// managedKeys - top level keys `aptible context` owns
+var managedKeys = []string{"contexts", "current-context"}
+
+// configTemplate - what `config init` writes, every setting commented out
+// with its default so the file documents itself
+func configTemplate(cmd *cobra.Command) string {
+	var b strings.Builder
+	b.WriteString("# aptible settings, `aptible config view` shows the values in effect.\n")
+	b.WriteString("# Flags and APTIBLE_* environment variables override this file.\n")
+	section := ""
+	for _, s := range config.Settings {
+		if s.Secret {
+			continue
+		}
+		def := s.Default
+		if flag := cmd.Flags().Lookup(s.Key); flag != nil {
+			def = flag.DefValue
+		}
+
+		key := s.Key
+		indent := ""
+		if i := strings.Index(s.Key, "."); i >= 0 {
+			if s.Key[:i] != section {
+				section = s.Key[:i]
+				fmt.Fprintf(&b, "# %s:\n", section)
+			}
+			key = s.Key[i+1:]
+			indent = "  "
+		}
+		if def == "" {
+			def = `""`
+		}
+		fmt.Fprintf(&b, "# %s%s: %s # %s\n", indent, key, def, s.Description)
+	}
+	return b.String()
+}
+
+// configInitRun - writes a commented config file
+func configInitRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		filePath, err := config.ConfigFilePath(viper.GetViper())
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filePath); err == nil && !configOptions.Force {
+			return fmt.Errorf("%s already exists, pass --force to overwrite it", filePath)
+		}
+		if err := os.WriteFile(filePath, []byte(configTemplate(cmd)), 0600); err != nil {
+			return err
+		}
+
+		fmt.Printf("Created %s.\n", filePath)
+		return nil
+	}
+}
+
+// configGetRun - the value commands would use for a key
+func configGetRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if _, ok := config.FindSetting(key); !ok {
+			if _, found := f.Get(strings.Split(key, ".")...); !found {
+				return unknownKeyError(key)
+			}
+		}
+
+		value := config.Lookup(viper.GetViper(), cmd, f, key)
+		if value.Source == "" {
+			return fmt.Errorf("%s is not set", key)
+		}
+		fmt.Println(value.Value)
+		return nil
+	}
+}
+
+// configSetRun - validates a value and writes it to the config file
+func configSetRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		key, raw := args[0], args[1]
+		if err := checkManagedKey(key); err != nil {
+			return err
+		}
+		var value interface{} = raw
+		setting, ok := config.FindSetting(key)
+		switch {
+		case ok:
+			parsed, err := setting.Parse(raw)
+			if err != nil {
+				return err
+			}
+			value = parsed
+		case !configOptions.Force:
+			return fmt.Errorf("%w, pass --force to set it anyway", unknownKeyError(key))
+		}
+
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if err := f.Set(value, strings.Split(key, ".")...); err != nil {
+			return fmt.Errorf("unable to set %s: %w", key, err)
+		}
+		if err := f.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Set %s in %s.\n", key, f.Path)
+		warnShadowed(cmd, f, key)
+		return nil
+	}
+}
+
+// configUnsetRun - removes a key from the config file
+func configUnsetRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		key := args[0]
+		if err := checkManagedKey(key); err != nil {
+			return err
+		}
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if !f.Unset(strings.Split(key, ".")...) {
+			fmt.Printf("%s is not set in %s.\n", key, f.Path)
+			return nil
+		}
+		if err := f.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Unset %s in %s.\n", key, f.Path)
+		warnShadowed(cmd, f, key)
+		return nil
+	}
+}
+
+// configViewRun - every setting, its value and where the value came from
+func configViewRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		v := viper.GetViper()
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+
+		values := []config.Value{}
+		secret := map[string]bool{}
+		for _, s := range config.Settings {
+			values = append(values, config.Lookup(v, cmd, f, s.Key))
+			secret[s.Key] = s.Secret
+		}
+		// keys the cli does not know are kept, e.g. for another version of it
+		unknown := []string{}
+		for _, key := range f.Leaves(managedKeys...) {
+			if _, ok := config.FindSetting(key); !ok {
+				unknown = append(unknown, key)
+			}
+		}
+		sort.Strings(unknown)
+		for _, key := range unknown {
+			values = append(values, config.Lookup(v, cmd, f, key))
+		}
+		for i := range values {
+			if secret[values[i].Key] && values[i].Value != "" {
+				values[i].Value = "(redacted)"
+			}
+		}
+
+		// TODO - print with tea
+		fmt.Println("Setting(s) List")
+		fmt.Println(libconfig.SettingTable(values).View())
+		return nil
+	}
+}
+
+// configPathRun - the config file commands read
+func configPathRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		filePath, err := config.ConfigFilePath(viper.GetViper())
+		if err != nil {
+			return err
+		}
+		fmt.Println(filePath)
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			fmt.Fprintln(os.Stderr, "It does not exist yet, see `aptible config init`.")
+		}
+		return nil
+	}
+}
+
+func unknownKeyError(key string) error {
+	return fmt.Errorf("unknown key %s, see `aptible config view` for the known ones", key)
+}
+
+func checkManagedKey(key string) error {
+	for _, managed := range managedKeys {
+		if key == managed || strings.HasPrefix(key, managed+".") {
+			return fmt.Errorf("%s is managed by `aptible context`", managed)
+		}
+	}
+	return nil
+}
+
+// warnShadowed - the top level of the file is the lowest precedence source,
+// say so when the change will not be seen
+func warnShadowed(cmd *cobra.Command, f *config.File, key string) {
+	value := config.Lookup(viper.GetViper(), cmd, f, key)
+	switch value.Source {
+	case config.SourceEnv, config.SourceContext:
+		fmt.Fprintf(os.Stderr, "Note: %s %s overrides the file's %s.\n", value.Source, value.Origin, key)
+	}
+}
+
+// NewConfigCmd - read and write the config file
+func NewConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "The config subcommand reads and writes the config file.",
+		Long: `The config subcommand reads and writes the config file, $HOME/.aptible.yaml unless --common names another.
+
+Known keys are validated before they are written, keys the cli does not know and comments are left as they are.`,
+	}
+
+	configInitCmd := &cobra.Command{
+		Use:   "init",
+		Short: "create a config file listing every setting.",
+		Long:  `The config init command creates a config file with every setting commented out next to its default.`,
+		Args:  cobra.NoArgs,
+		RunE:  configInitRun(),
+	}
+	configInitCmd.Flags().BoolVarP(&configOptions.Force, "force", "", false, "overwrite an existing config file")
+
+	configGetCmd := &cobra.Command{
+		Use:   "get [key]",
+		Short: "print the value commands use for a key.",
+		Long:  `The config get command prints the value commands use for a key, taking flags, APTIBLE_* environment variables, the current context and defaults into account.`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  configGetRun(),
+	}
+
+	configSetCmd := &cobra.Command{
+		Use:   "set [key] [value]",
+		Short: "write a key to the config file.",
+		Long: `The config set command validates a value and writes it to the top level of the config file, e.g.
+
+  aptible config set retry.max-attempts 5`,
+		Args: cobra.ExactArgs(2),
+		RunE: configSetRun(),
+	}
+	configSetCmd.Flags().BoolVarP(&configOptions.Force, "force", "", false, "write a key the cli does not know")
+
+	configUnsetCmd := &cobra.Command{
+		Use:   "unset [key]",
+		Short: "remove a key from the config file.",
+		Long:  `The config unset command removes a key from the top level of the config file.`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  configUnsetRun(),
+	}
+
+	configViewCmd := &cobra.Command{
+		Use:   "view",
+		Short: "show every setting and where its value came from.",
+		Long:  `The config view command lists every setting with the value commands use and its source: a flag, an environment variable, the current context, the config file or a default.  Secrets are redacted.`,
+		Args:  cobra.NoArgs,
+		RunE:  configViewRun(),
+	}
+
+	configPathCmd := &cobra.Command{
+		Use:   "path",
+		Short: "print the path of the config file.",
+		Long:  `The config path command prints the path of the config file, whether or not it exists yet.`,
+		Args:  cobra.NoArgs,
+		RunE:  configPathRun(),
+	}
+
+	configCmd.AddCommand(configInitCmd, configGetCmd, configSetCmd, configUnsetCmd, configViewCmd, configPathCmd)
+
+	return configCmd
+}
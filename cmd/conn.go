@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -8,7 +9,9 @@ import (
 
 	cac "github.com/aptible/cloud-api-clients/clients/go"
 	"github.com/aptible/cloud-cli/config"
+	libasset "github.com/aptible/cloud-cli/lib/asset"
 	"github.com/aptible/cloud-cli/lib/conn"
+	libenv "github.com/aptible/cloud-cli/lib/env"
 	"github.com/aptible/cloud-cli/ui/fetch"
 	"github.com/aptible/cloud-cli/ui/form"
 )
@@ -17,13 +20,19 @@ type ConnOptions struct {
 	OutAsset    string
 	InAsset     string
 	Description string
+	Asset       string
+	Connection  string
 }
 
 var connOptions = ConnOptions{}
 
 func connCreateRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 		formResult := form.FormResult{
 			Org:         config.Vconfig.GetString("org"),
 			Env:         config.Vconfig.GetString("env"),
@@ -32,9 +41,9 @@ func connCreateRun() config.CobraRunE {
 			Description: connOptions.Description,
 		}
 
-		err := libconn.ConnCreateForm(config, &formResult)
+		err = libconn.ConnCreateForm(config, &formResult)
 		if err != nil {
-			return nil
+			return err
 		}
 
 		params := cac.ConnectionInput{
@@ -47,8 +56,9 @@ func connCreateRun() config.CobraRunE {
 			formResult.OutAsset,
 			formResult.InAsset,
 		)
-		model := fetch.NewModel(msg, func() (interface{}, error) {
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
 			return config.Cc.CreateConnection(
+				ctx,
 				formResult.Org,
 				formResult.Env,
 				formResult.InAsset,
@@ -66,6 +76,154 @@ func connCreateRun() config.CobraRunE {
 	}
 }
 
+func connListRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+		formResult := form.FormResult{
+			Org:   config.Vconfig.GetString("org"),
+			Env:   config.Vconfig.GetString("env"),
+			Asset: connOptions.Asset,
+		}
+		err = libenv.EnvForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+		err = libasset.ResolveAssetForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("getting connections for environment %s", formResult.Env)
+		if formResult.Asset != "" {
+			msg = fmt.Sprintf("getting connections for asset %s", formResult.Asset)
+		}
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return config.Cc.ListConnections(ctx, formResult.Org, formResult.Env, formResult.Asset)
+		})
+		data, err := fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+
+		conns := data.Result.([]cac.ConnectionOutput)
+		if len(conns) == 0 {
+			// TODO - print with tea
+			fmt.Println("No connections found.")
+			return nil
+		}
+
+		connTable := libconn.ConnTable(conns)
+		// TODO - print with tea
+		fmt.Println("Connection(s) List")
+		fmt.Println(connTable.View())
+
+		return nil
+	}
+}
+
+// findConn - the connection picked by the user along with the asset it belongs to
+func findConn(config *config.CloudConfig, formResult *form.FormResult) (*cac.ConnectionOutput, error) {
+	msg := fmt.Sprintf("describing connection %s", formResult.Connection)
+	model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+		if formResult.Asset != "" {
+			return config.Cc.DescribeConnection(ctx, formResult.Org, formResult.Env, formResult.Asset, formResult.Connection)
+		}
+		return libconn.FindConnection(ctx, config.Cc, formResult.Org, formResult.Env, "", formResult.Connection)
+	})
+	data, err := fetch.WithOutput(model)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := data.Result.(*cac.ConnectionOutput)
+	if formResult.Asset == "" {
+		formResult.Asset = libconn.AssetId(*conn)
+	}
+	return conn, nil
+}
+
+func connShowRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+		formResult := form.FormResult{
+			Org:        config.Vconfig.GetString("org"),
+			Env:        config.Vconfig.GetString("env"),
+			Asset:      connOptions.Asset,
+			Connection: connOptions.Connection,
+		}
+		err = libconn.ConnDescribeForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		conn, err := findConn(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(libconn.ConnTable(conn).View())
+		return nil
+	}
+}
+
+func connDestroyRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+		formResult := form.FormResult{
+			Org:        config.Vconfig.GetString("org"),
+			Env:        config.Vconfig.GetString("env"),
+			Asset:      connOptions.Asset,
+			Connection: connOptions.Connection,
+		}
+		err = libconn.ConnDescribeForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		// the api needs the asset the connection belongs to
+		if formResult.Asset == "" {
+			_, err = findConn(config, &formResult)
+			if err != nil {
+				return err
+			}
+			if formResult.Asset == "" {
+				return fmt.Errorf("connection %s does not say which asset it belongs to, pass it with --asset", formResult.Connection)
+			}
+		}
+
+		msg := fmt.Sprintf("destroying connection %s", formResult.Connection)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			err := config.Cc.DestroyConnection(
+				ctx,
+				formResult.Org,
+				formResult.Env,
+				formResult.Asset,
+				formResult.Connection,
+			)
+			return nil, err
+		})
+		_, err = fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Started request to destroy connection with id: %s\n", formResult.Connection)
+		return nil
+	}
+}
+
 // NewConnectionCmd - create a connection between two assets.
 func NewConnectionCmd() *cobra.Command {
 	connCmd := &cobra.Command{
@@ -83,11 +241,44 @@ func NewConnectionCmd() *cobra.Command {
 		RunE:    connCreateRun(),
 	}
 
-	connCreateCmd.Flags().StringVarP(&connOptions.OutAsset, "outgoing-asset", "", "", "The outgoing asset is the asset that wants to connect to another asset.")
-	connCreateCmd.Flags().StringVarP(&connOptions.InAsset, "incoming-asset", "", "", "The incoming asset is the asset that the outgoing asset connects to.")
+	connListCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "list connections of an asset or environment.",
+		Long:    `The connection list command lists the connections of a single asset with --asset, or of every asset in the environment.`,
+		Aliases: []string{"ls"},
+		RunE:    connListRun(),
+	}
+
+	connShowCmd := &cobra.Command{
+		Use:     "show",
+		Short:   "show a connection.",
+		Long:    `The connection show command describes a single connection.`,
+		Aliases: []string{"describe"},
+		RunE:    connShowRun(),
+	}
+
+	connDestroyCmd := &cobra.Command{
+		Use:     "destroy",
+		Short:   "remove a connection between two assets.",
+		Long:    `The connection destroy command removes a connection, without --connection a connection can be picked from the environment or --asset.`,
+		Aliases: []string{"d", "delete", "rm", "remove"},
+		RunE:    connDestroyRun(),
+	}
+
+	connCreateCmd.Flags().StringVarP(&connOptions.OutAsset, "outgoing-asset", "", "", "The outgoing asset is the asset that wants to connect to another asset, by id or name.")
+	connCreateCmd.Flags().StringVarP(&connOptions.InAsset, "incoming-asset", "", "", "The incoming asset is the asset that the outgoing asset connects to, by id or name.")
 	connCreateCmd.Flags().StringVarP(&connOptions.Description, "description", "", "", "Describe the connection")
 
+	connListCmd.Flags().StringVarP(&connOptions.Asset, "asset", "", "", "only list the connections of this asset, by id or name")
+	connShowCmd.Flags().StringVarP(&connOptions.Asset, "asset", "", "", "asset id or name the connection belongs to (the incoming asset)")
+	connDestroyCmd.Flags().StringVarP(&connOptions.Asset, "asset", "", "", "asset id or name the connection belongs to (the incoming asset)")
+	connShowCmd.Flags().StringVarP(&connOptions.Connection, "connection", "", "", "connection id")
+	connDestroyCmd.Flags().StringVarP(&connOptions.Connection, "connection", "", "", "connection id")
+
 	connCmd.AddCommand(connCreateCmd)
+	connCmd.AddCommand(connListCmd)
+	connCmd.AddCommand(connShowCmd)
+	connCmd.AddCommand(connDestroyCmd)
 
 	return connCmd
 }
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/aptible/cloud-cli/config"
+	libcontext "github.com/aptible/cloud-cli/lib/context"
+)
+
+type ContextOptions struct {
+	Use bool
+}
+
+var contextOptions = ContextOptions{}
+
+// loadConfigFile - the config file viper read, or the one `context create`
+// will write
+func loadConfigFile() (*config.File, error) {
+	filePath, err := config.ConfigFilePath(viper.GetViper())
+	if err != nil {
+		return nil, err
+	}
+	return config.LoadFile(filePath)
+}
+
+// loadContext - the config file, failing when it does not define name
+func loadContext(name string) (*config.File, error) {
+	f, err := loadConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	if !config.HasContext(f, name) {
+		return nil, fmt.Errorf("context %q does not exist, see `aptible context list`", name)
+	}
+	return f, nil
+}
+
+// contextListRun - the contexts in the config file
+func contextListRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+
+		contexts := config.Contexts(f, config.ActiveContext())
+		if len(contexts) == 0 {
+			fmt.Println("No contexts, add one with `aptible context create`.")
+			return nil
+		}
+
+		// TODO - print with tea
+		fmt.Println("Context(s) List")
+		fmt.Println(libcontext.ContextTable(contexts).View())
+		return nil
+	}
+}
+
+// contextUseRun - makes a context the default for every command
+func contextUseRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		f, err := loadContext(args[0])
+		if err != nil {
+			return err
+		}
+		if err := f.Set(args[0], "current-context"); err != nil {
+			return err
+		}
+		if err := f.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Switched to context %s.\n", args[0])
+		return nil
+	}
+}
+
+// contextCreateRun - stores the api-domain, auth-domain, org and env flags
+// under a new context
+func contextCreateRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := config.ValidateContextName(name); err != nil {
+			return err
+		}
+		f, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if config.HasContext(f, name) {
+			return fmt.Errorf("context %q already exists, delete it first to start over", name)
+		}
+
+		settings := map[string]string{}
+		for _, key := range config.ContextKeys {
+			// only what was typed, defaults and the active context stay out
+			if flag := cmd.Flags().Lookup(key); flag != nil && flag.Changed {
+				settings[key] = flag.Value.String()
+			}
+		}
+		if err := f.Set(settings, "contexts", name); err != nil {
+			return err
+		}
+		if contextOptions.Use {
+			if err := f.Set(name, "current-context"); err != nil {
+				return err
+			}
+		}
+		if err := f.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Created context %s in %s.\n", name, f.Path)
+		if contextOptions.Use {
+			fmt.Printf("Switched to context %s.\n", name)
+		}
+		return nil
+	}
+}
+
+// contextDeleteRun - removes a context, current-context is cleared when it
+// pointed at it
+func contextDeleteRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		f, err := loadContext(name)
+		if err != nil {
+			return err
+		}
+		f.Unset("contexts", name)
+		current := f.GetString("current-context") == name
+		if current {
+			f.Unset("current-context")
+		}
+		if err := f.Save(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted context %s.\n", name)
+		if current {
+			fmt.Println("It was the current context, no context is in use now.")
+		}
+		return nil
+	}
+}
+
+// contextCurrentRun - the context commands run in
+func contextCurrentRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		name := config.ActiveContext()
+		if name == "" {
+			return fmt.Errorf("no context in use, see `aptible context use`")
+		}
+		fmt.Println(name)
+		return nil
+	}
+}
+
+// NewContextCmd - switch between named sets of settings
+func NewContextCmd() *cobra.Command {
+	contextCmd := &cobra.Command{
+		Use:   "context",
+		Short: "The context subcommand manages named sets of settings.",
+		Long: `The context subcommand manages named sets of settings, e.g. one for production and one for staging, kept in the config file.
+
+The current context's api-domain, auth-domain, org and env apply to every command.  Flags and APTIBLE_* environment variables still override them, and --context or APTIBLE_CONTEXT picks a context for a single command.`,
+	}
+
+	contextListCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "list the contexts, the current one is marked with a *.",
+		Long:    `The context list command lists the contexts in the config file, the current one is marked with a *.`,
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE:    contextListRun(),
+	}
+
+	contextUseCmd := &cobra.Command{
+		Use:   "use [name]",
+		Short: "make a context the current one.",
+		Long:  `The context use command makes a context the current one for every command that follows.`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  contextUseRun(),
+	}
+
+	contextCreateCmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "create a context from the --api-domain, --auth-domain, --org and --env flags.",
+		Long: `The context create command stores the --api-domain, --auth-domain, --org and --env flags given to it under a new context, e.g.
+
+  aptible context create staging --api-domain cloud-api.staging.example.com --auth-domain auth.staging.example.com --use`,
+		Args: cobra.ExactArgs(1),
+		RunE: contextCreateRun(),
+	}
+	contextCreateCmd.Flags().BoolVarP(&contextOptions.Use, "use", "", false, "make the new context the current one")
+
+	contextDeleteCmd := &cobra.Command{
+		Use:     "delete [name]",
+		Short:   "delete a context.",
+		Long:    `The context delete command removes a context from the config file, tokens for its auth domain stay in the credential store.`,
+		Aliases: []string{"rm"},
+		Args:    cobra.ExactArgs(1),
+		RunE:    contextDeleteRun(),
+	}
+
+	contextCurrentCmd := &cobra.Command{
+		Use:   "current",
+		Short: "print the name of the context in use.",
+		Long:  `The context current command prints the name of the context in use, taking --context and APTIBLE_CONTEXT into account.`,
+		Args:  cobra.NoArgs,
+		RunE:  contextCurrentRun(),
+	}
+
+	contextCmd.AddCommand(contextListCmd, contextUseCmd, contextCreateCmd, contextDeleteCmd, contextCurrentCmd)
+
+	return contextCmd
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/aptible/cloud-cli/client/fake"
+	"github.com/aptible/cloud-cli/config"
+)
+
+type DevOptions struct {
+	Listen   string
+	Step     time.Duration
+	Empty    bool
+	PageSize int
+
+	Email    string
+	Password string
+	OTP      string
+}
+
+var devOptions = DevOptions{}
+
+// devMockServerRun - serves an in-memory cloud api until interrupted
+func devMockServerRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		store := fake.NewSeeded()
+		if devOptions.Empty {
+			store = fake.New()
+		}
+		store.Step = devOptions.Step
+
+		api := fake.NewServer(store)
+		api.Log = os.Stderr
+		api.PageSize = devOptions.PageSize
+		auth := &fake.AuthServer{
+			Email:    devOptions.Email,
+			Password: devOptions.Password,
+			OTP:      devOptions.OTP,
+			Log:      os.Stderr,
+		}
+		// the token endpoint lives next to the api so one address serves
+		// as both --auth-domain and --api-domain
+		handler := http.NewServeMux()
+		handler.Handle("/tokens", auth)
+		handler.Handle("/", api)
+
+		listener, err := net.Listen("tcp", devOptions.Listen)
+		if err != nil {
+			return err
+		}
+		server := &http.Server{Handler: handler}
+
+		url := fmt.Sprintf("http://%s", listener.Addr())
+		fmt.Printf("Mock cloud api listening on %s, press ctrl+c to stop.\n", url)
+		if !devOptions.Empty {
+			fmt.Printf("Seeded organization %s with environment %s.\n", fake.DemoOrgId, fake.DemoEnvId)
+		}
+		fmt.Printf("Point the cli at it with:\n\taptible --api-domain %s --token anything org ls\n", url)
+		fmt.Printf("or log in, the password is %q, with:\n\taptible --auth-domain %s login --email %s\n\n", devOptions.Password, url, devOptions.Email)
+
+		ctx := cmd.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		go func() {
+			<-ctx.Done()
+			shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdown)
+		}()
+
+		err = server.Serve(listener)
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// NewDevCmd - tools for developing against, and demoing, the cli
+func NewDevCmd() *cobra.Command {
+	devCmd := &cobra.Command{
+		Use:   "dev",
+		Short: "The dev subcommand has tools for developing against the cloud api.",
+		Long:  `The dev subcommand has tools for developing against the cloud api without access to it.`,
+	}
+
+	devMockServerCmd := &cobra.Command{
+		Use:   "mock-server",
+		Short: "serve an in-memory cloud api.",
+		Long:  `The dev mock-server command serves the cloud api routes, and a token endpoint for login, from an in-memory store.  Assets and operations move through their statuses over time like they do against the real api.  Nothing is persisted and no AWS access is needed.`,
+		Args:  cobra.NoArgs,
+		RunE:  devMockServerRun(),
+	}
+
+	devMockServerCmd.Flags().StringVarP(&devOptions.Listen, "listen", "", "127.0.0.1:8080", "address to listen on")
+	devMockServerCmd.Flags().DurationVarP(&devOptions.Step, "step", "", 5*time.Second, "how long assets and operations stay in each intermediate status")
+	devMockServerCmd.Flags().BoolVarP(&devOptions.Empty, "empty", "", false, "start without the demo organization, environment and vpc")
+	devMockServerCmd.Flags().IntVarP(&devOptions.PageSize, "page-size", "", 0, "items per page of a list, 0 sends lists whole")
+
+	devMockServerCmd.Flags().StringVarP(&devOptions.Email, "email", "", "demo@example.com", "email accepted by the token endpoint")
+	devMockServerCmd.Flags().StringVarP(&devOptions.Password, "password", "", "password", "password accepted by the token endpoint")
+	devMockServerCmd.Flags().StringVarP(&devOptions.OTP, "otp", "", "", "require this one-time password on login")
+
+	devCmd.AddCommand(devMockServerCmd)
+
+	return devCmd
+}
@@ -1,39 +1,70 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"sort"
 
 	cac "github.com/aptible/cloud-api-clients/clients/go"
+	"github.com/aptible/cloud-cli/client"
 	"github.com/aptible/cloud-cli/config"
 	"github.com/aptible/cloud-cli/lib/env"
+	libkv "github.com/aptible/cloud-cli/lib/kv"
+	libop "github.com/aptible/cloud-cli/lib/op"
 	liborg "github.com/aptible/cloud-cli/lib/org"
+	libresolve "github.com/aptible/cloud-cli/lib/resolve"
+	"github.com/aptible/cloud-cli/ui/common"
 	"github.com/aptible/cloud-cli/ui/fetch"
 	"github.com/aptible/cloud-cli/ui/form"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+type EnvOptions struct {
+	Name        string
+	Description string
+	Data        []string
+	Page        client.PageOptions
+	Clear       bool
+}
+
+var envOptions = EnvOptions{}
+
+// recentOps - how many operations env show lists
+const recentOps = 5
+
 // envCreateRun - create an environment
 func envCreateRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 		org := config.Vconfig.GetString("org")
 
 		formResult := form.FormResult{Org: org}
-		err := liborg.OrgForm(config, &formResult)
+		err = liborg.OrgForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		data, err := libkv.Parse(envOptions.Data)
 		if err != nil {
 			return err
 		}
 
-		desc := ""
 		params := cac.EnvironmentInput{
-			Name:        args[0],
-			Description: &desc,
-			Data:        map[string]interface{}{},
+			Name: args[0],
+			Data: libkv.Merge(map[string]interface{}{}, data),
+		}
+		// like env update, an unset --description is left for the api to default
+		if cmd.Flags().Changed("description") {
+			params.Description = &envOptions.Description
 		}
 
-		progressModel := fetch.NewModel("creating environment", func() (interface{}, error) {
-			return config.Cc.CreateEnvironment(formResult.Org, params)
+		progressModel := fetch.NewModel(config.Ctx, "creating environment", func(ctx context.Context) (interface{}, error) {
+			return config.Cc.CreateEnvironment(ctx, formResult.Org, params)
 		})
 
 		result, err := fetch.WithOutput(progressModel)
@@ -49,62 +80,260 @@ func envCreateRun() config.CobraRunE {
 	}
 }
 
+// envView - every detail of an environment along with its assets and recent operations
+func envView(summary *libenv.Summary) string {
+	env := summary.Env
+	desc := ""
+	if env.Description != nil {
+		desc = *env.Description
+	}
+	details := []string{
+		"Id", env.Id,
+		"Name", env.Name,
+		"Description", desc,
+		"AWS Account", libenv.SafeString(env.AwsAccountId),
+		"Assets", libenv.FormatAssetCounts(summary.AssetCounts),
+	}
+
+	keys := make([]string, 0, len(env.Data))
+	for key := range env.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		details = append(details, key, fmt.Sprint(env.Data[key]))
+	}
+
+	s := common.KeyValueView(details...)
+	if len(summary.RecentOps) > 0 {
+		s += "\n\nRecent Operations\n"
+		s += libop.OpTable(summary.RecentOps).View()
+	}
+	return s
+}
+
+// envShowRun - describe an environment
+func envShowRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+
+		formResult := form.FormResult{
+			Org: config.Vconfig.GetString("org"),
+			Env: config.Vconfig.GetString("env"),
+		}
+		if len(args) > 0 {
+			formResult.Env = args[0]
+		}
+		err = libenv.EnvForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("describing environment %s", formResult.Env)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return libenv.Summarize(ctx, config.Cc, formResult.Org, formResult.Env, recentOps)
+		})
+		result, err := fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(envView(result.Result.(*libenv.Summary)))
+		return nil
+	}
+}
+
+// envUpdateRun - rename an environment or change its description and data
+func envUpdateRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		data, err := libkv.Parse(envOptions.Data)
+		if err != nil {
+			return err
+		}
+		// --description "" clears the description so look at whether it was passed
+		descChanged := cmd.Flags().Changed("description")
+		if envOptions.Name == "" && !descChanged && len(data) == 0 {
+			return fmt.Errorf("nothing to update, pass --name, --description or --data")
+		}
+
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+		formResult := form.FormResult{
+			Org: config.Vconfig.GetString("org"),
+			Env: config.Vconfig.GetString("env"),
+		}
+		if len(args) > 0 {
+			formResult.Env = args[0]
+		}
+		err = libenv.EnvForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("updating environment %s", formResult.Env)
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			// the api replaces the whole environment so start from what is there
+			env, err := config.Cc.DescribeEnvironment(ctx, formResult.Org, formResult.Env)
+			if err != nil {
+				return nil, err
+			}
+
+			params := cac.EnvironmentInput{
+				Name:        env.Name,
+				Description: env.Description,
+				Data:        libkv.Merge(env.Data, data),
+			}
+			if envOptions.Name != "" {
+				params.Name = envOptions.Name
+			}
+			if descChanged {
+				params.Description = &envOptions.Description
+			}
+			return config.Cc.UpdateEnvironment(ctx, formResult.Org, formResult.Env, params)
+		})
+		result, err := fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+
+		envTable := libenv.EnvTable(result.Result.(*cac.EnvironmentOutput))
+		// TODO - print with tea
+		fmt.Println("Updated Environment(s)")
+		fmt.Println(envTable.View())
+		return nil
+	}
+}
+
 // envDestroyRun - destroy an environment
 func envDestroyRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
-		org := config.Vconfig.GetString("org")
-		env := args[0]
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 
-		formResult := form.FormResult{Org: org, Env: env}
-		err := libenv.EnvForm(config, &formResult)
+		formResult := form.FormResult{
+			Org: config.Vconfig.GetString("org"),
+			Env: config.Vconfig.GetString("env"),
+		}
+		if len(args) > 0 {
+			formResult.Env = args[0]
+		}
+		err = libenv.EnvDestroyForm(config, &formResult)
 		if err != nil {
 			return err
 		}
 
-		model := fetch.NewModel("destroying environment", func() (interface{}, error) {
-			err := config.Cc.DestroyEnvironment(formResult.Org, formResult.Env)
+		model := fetch.NewModel(config.Ctx, "destroying environment", func(ctx context.Context) (interface{}, error) {
+			err := config.Cc.DestroyEnvironment(ctx, formResult.Org, formResult.Env)
 			return nil, err
 		})
 
 		err = fetch.Any(model)
+		if err != nil {
+			return err
+		}
 
 		// does not print anything, no table to print here
-		fmt.Printf("Destroyed environment: %s\n", env)
-		return err
+		fmt.Printf("Destroyed environment: %s\n", formResult.Env)
+		return nil
 	}
 }
 
 // envListRun - lists all environments for an org id
 func envListRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
+		if err := envOptions.Page.Validate(); err != nil {
+			return err
+		}
+
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 		org := config.Vconfig.GetString("org")
 
 		formResult := form.FormResult{Org: org}
-		err := liborg.OrgForm(config, &formResult)
+		err = liborg.OrgForm(config, &formResult)
 		if err != nil {
 			return err
 		}
 
-		model := fetch.NewModel("fetching environments", func() (interface{}, error) {
-			return config.Cc.ListEnvironments(formResult.Org)
+		pager := config.Cc.EnvironmentPages(formResult.Org, envOptions.Page)
+		count, err := fetch.Pages(config.Ctx, "fetching environments", pager, func(envs []cac.EnvironmentOutput, first bool) {
+			// TODO - print with tea
+			if first {
+				fmt.Println("Environment(s) List")
+			}
+			fmt.Println(libenv.EnvTable(envs).WithHeaderVisibility(first).View())
 		})
-		result, err := fetch.WithOutput(model)
 		if err != nil {
 			return err
 		}
-		if result == nil {
+		if count == 0 {
 			// TODO - print with tea
 			fmt.Println("No environments found.")
-			return nil
 		}
 
-		envTable := libenv.EnvTable(result.Result.([]cac.EnvironmentOutput))
-		// TODO - print with tea
-		fmt.Println("Environment(s) List")
-		fmt.Println(envTable.View())
+		return nil
+	}
+}
 
+// envUseRun - stores the environment forms start on
+func envUseRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		if envOptions.Clear {
+			if len(args) > 0 {
+				return fmt.Errorf("--clear does not take an environment")
+			}
+			return clearDefault("default-env", "environment")
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("pass the id or name of an environment, or --clear")
+		}
+
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+		formResult := form.FormResult{Org: config.Vconfig.GetString("org")}
+		err = liborg.OrgForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		model := fetch.NewModel(config.Ctx, "fetching environments", func(ctx context.Context) (interface{}, error) {
+			return config.Cc.ListEnvironments(ctx, formResult.Org)
+		})
+		result, err := fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+
+		candidates := []libresolve.Candidate{}
+		for _, env := range result.Result.([]cac.EnvironmentOutput) {
+			candidates = append(candidates, libresolve.Candidate{Id: env.Id, Name: env.Name})
+		}
+		env, err := libresolve.One("environment", args[0], candidates)
+		if err != nil {
+			return err
+		}
+
+		location, err := saveDefault("default-env", env.Id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Default environment is now %s in %s.\n", env, location)
 		return nil
 	}
 }
@@ -128,13 +357,32 @@ func NewEnvCmd() *cobra.Command {
 	}
 
 	envDestroyCmd := &cobra.Command{
-		Use:     "destroy [env_id]",
+		Use:     "destroy [env_id|env_name]",
 		Short:   "permentantly remove the environment.",
 		Long:    `The environment destroy command will permanently remove the environment.`,
 		Aliases: []string{"d", "delete", "rm", "remove"},
+		Args:    cobra.MaximumNArgs(1),
 		RunE:    envDestroyRun(),
 	}
 
+	envShowCmd := &cobra.Command{
+		Use:     "show [env_id|env_name]",
+		Short:   "show an environment.",
+		Long:    `The environment show command describes an environment, how many assets it holds by status and its most recent operations.`,
+		Aliases: []string{"describe"},
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    envShowRun(),
+	}
+
+	envUpdateCmd := &cobra.Command{
+		Use:     "update [env_id|env_name]",
+		Short:   "rename an environment or change its description and data.",
+		Long:    `The environment update command renames an environment with --name, changes its description with --description and its data with --data key=value, an empty value removes the key.`,
+		Aliases: []string{"u", "edit"},
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    envUpdateRun(),
+	}
+
 	envListCmd := &cobra.Command{
 		Use:     "list",
 		Short:   "list all environment within an organization.",
@@ -143,9 +391,28 @@ func NewEnvCmd() *cobra.Command {
 		RunE:    envListRun(),
 	}
 
+	envUseCmd := &cobra.Command{
+		Use:   "use [env_id|env_name]",
+		Short: "pre-select an environment in every environment prompt.",
+		Long:  `The environment use command stores the environment that environment prompts start on, marked "(current)".  It is kept in the current context when there is one.  --clear removes it, --env still skips the prompt.`,
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  envUseRun(),
+	}
+
+	envCreateCmd.Flags().StringVarP(&envOptions.Description, "description", "", "", "describe the environment")
+	envCreateCmd.Flags().StringArrayVarP(&envOptions.Data, "data", "", []string{}, "metadata as key=value, can be repeated")
+	envUpdateCmd.Flags().StringVarP(&envOptions.Name, "name", "", "", "new name for the environment")
+	envUpdateCmd.Flags().StringVarP(&envOptions.Description, "description", "", "", "new description, pass an empty value to clear it")
+	envUpdateCmd.Flags().StringArrayVarP(&envOptions.Data, "data", "", []string{}, "metadata as key=value, an empty value removes the key, can be repeated")
+	config.AddPageFlags(envListCmd, &envOptions.Page)
+	envUseCmd.Flags().BoolVarP(&envOptions.Clear, "clear", "", false, "remove the default environment")
+
 	envCmd.AddCommand(envCreateCmd)
+	envCmd.AddCommand(envShowCmd)
+	envCmd.AddCommand(envUpdateCmd)
 	envCmd.AddCommand(envDestroyCmd)
 	envCmd.AddCommand(envListCmd)
+	envCmd.AddCommand(envUseCmd)
 
 	return envCmd
 }
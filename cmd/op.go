@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/aptible/cloud-cli/client"
+	"github.com/aptible/cloud-cli/config"
+	libasset "github.com/aptible/cloud-cli/lib/asset"
+	"github.com/aptible/cloud-cli/lib/op"
+	"github.com/aptible/cloud-cli/lib/org"
+	"github.com/aptible/cloud-cli/ui/common"
+	"github.com/aptible/cloud-cli/ui/fetch"
+	"github.com/aptible/cloud-cli/ui/form"
+)
+
+type OpOptions struct {
+	Asset  string
+	Type   string
+	Status string
+	Page   client.PageOptions
+}
+
+var opOptions = OpOptions{}
+
+// opListRun - lists the operations of an organization
+func opListRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		filter := libop.Filter{Type: opOptions.Type, Status: opOptions.Status}
+		if err := filter.Validate(); err != nil {
+			return err
+		}
+		if err := opOptions.Page.Validate(); err != nil {
+			return err
+		}
+
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+		formResult := form.FormResult{
+			Org:   config.Vconfig.GetString("org"),
+			Env:   config.Vconfig.GetString("env"),
+			Asset: opOptions.Asset,
+		}
+		err = liborg.OrgForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+		// operations belong to the org, an environment is only asked for
+		// to find an asset given by name
+		err = libasset.ResolveAssetForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		// with --type or --status the limit counts matching operations,
+		// not the ones read from the api
+		pageOpts := opOptions.Page
+		filtering := filter.Type != "" || filter.Status != ""
+		if filtering {
+			pageOpts.Limit = 0
+		}
+		var pager *client.Pager[cac.OperationOutput]
+		if formResult.Asset != "" {
+			pager = config.Cc.OperationPagesByAsset(formResult.Org, formResult.Asset, pageOpts)
+		} else {
+			pager = config.Cc.OperationPages(formResult.Org, pageOpts)
+		}
+
+		msg := fmt.Sprintf("getting operations for organization %s", formResult.Org)
+		shown := 0
+		_, err = fetch.Pages(config.Ctx, msg, pager, func(page []cac.OperationOutput, _ bool) {
+			ops := libop.FilterOps(page, filter)
+			if limit := opOptions.Page.Limit; filtering && limit > 0 && shown+len(ops) >= limit {
+				ops = ops[:limit-shown]
+				pager.Stop()
+			}
+			if len(ops) == 0 {
+				return
+			}
+			// TODO - print with tea
+			if shown == 0 {
+				fmt.Println("Operation(s) List")
+			}
+			fmt.Println(libop.OpTable(ops).WithHeaderVisibility(shown == 0).View())
+			shown += len(ops)
+		})
+		if err != nil {
+			return err
+		}
+		if shown == 0 {
+			// TODO - print with tea
+			fmt.Println("No operations found.")
+		}
+
+		return nil
+	}
+}
+
+// opView - every detail of a single operation, the table truncates long errors
+func opView(op *cac.OperationOutput) string {
+	duration := ""
+	if d := libop.Duration(*op); d > 0 {
+		duration = d.String()
+	}
+	return common.KeyValueView(
+		"Id", op.Id,
+		"Type", libop.TypeName(*op),
+		"Status", libop.StatusName(*op),
+		"Asset", op.AssetId,
+		"Created", libop.FormatTime(op.CreatedAt),
+		"Updated", libop.FormatTime(op.UpdatedAt),
+		"Duration", duration,
+		"Error", libop.ErrorMessage(*op),
+	)
+}
+
+// opRun - runs a single operation request and prints the operation it returns
+func opRun(verb string, fn func(ctx context.Context, cfg *config.CloudConfig, orgId, opId string) (*cac.OperationOutput, error)) config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+		formResult := form.FormResult{Org: config.Vconfig.GetString("org")}
+		err = liborg.OrgForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("%s operation %s", verb, args[0])
+		model := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return fn(ctx, config, formResult.Org, args[0])
+		})
+		data, err := fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(opView(data.Result.(*cac.OperationOutput)))
+		return nil
+	}
+}
+
+func opShowRun() config.CobraRunE {
+	return opRun("describing", func(ctx context.Context, cfg *config.CloudConfig, orgId, opId string) (*cac.OperationOutput, error) {
+		return cfg.Cc.DescribeOperation(ctx, orgId, opId)
+	})
+}
+
+func opCancelRun() config.CobraRunE {
+	return opRun("canceling", func(ctx context.Context, cfg *config.CloudConfig, orgId, opId string) (*cac.OperationOutput, error) {
+		return cfg.Cc.CancelOperation(ctx, orgId, opId)
+	})
+}
+
+func opRetryRun() config.CobraRunE {
+	return opRun("retrying", func(ctx context.Context, cfg *config.CloudConfig, orgId, opId string) (*cac.OperationOutput, error) {
+		return cfg.Cc.RetryOperation(ctx, orgId, opId)
+	})
+}
+
+// NewOperationCmd - inspect and manage the operations that provision assets
+func NewOperationCmd() *cobra.Command {
+	opCmd := &cobra.Command{
+		Use:     "operation",
+		Short:   "The operation subcommand helps inspect and manage operations on your Aptible assets.",
+		Long:    `The operation subcommand helps inspect and manage operations on your Aptible assets.`,
+		Aliases: []string{"op", "ops"},
+	}
+
+	opListCmd := &cobra.Command{
+		Use:     "list",
+		Short:   "list operations within an organization.",
+		Long:    `The operation list command lists the operations within an organization.  Each page is printed newest first as it arrives, pages come in the order the api returns them.`,
+		Aliases: []string{"ls"},
+		Args:    cobra.NoArgs,
+		RunE:    opListRun(),
+	}
+
+	opShowCmd := &cobra.Command{
+		Use:     "show [operation_id]",
+		Short:   "show an operation.",
+		Long:    `The operation show command describes an operation, including why it failed.`,
+		Aliases: []string{"describe"},
+		Args:    cobra.ExactArgs(1),
+		RunE:    opShowRun(),
+	}
+
+	opCancelCmd := &cobra.Command{
+		Use:   "cancel [operation_id]",
+		Short: "cancel an operation that has not finished.",
+		Long:  `The operation cancel command stops an operation that is still pending or in progress.`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  opCancelRun(),
+	}
+
+	opRetryCmd := &cobra.Command{
+		Use:   "retry [operation_id]",
+		Short: "retry a failed or canceled operation.",
+		Long:  `The operation retry command starts a new operation that repeats a failed or canceled one.`,
+		Args:  cobra.ExactArgs(1),
+		RunE:  opRetryRun(),
+	}
+
+	opListCmd.Flags().StringVarP(&opOptions.Asset, "asset", "", "", "only list operations for this asset, by id or name")
+	opListCmd.Flags().StringVarP(&opOptions.Type, "type", "", "", "only list operations of this type, e.g. APPLY or DESTROY")
+	opListCmd.Flags().StringVarP(&opOptions.Status, "status", "", "", "only list operations with this status, e.g. FAILED")
+	config.AddPageFlags(opListCmd, &opOptions.Page)
+
+	opCmd.AddCommand(opListCmd)
+	opCmd.AddCommand(opShowCmd)
+	opCmd.AddCommand(opCancelCmd)
+	opCmd.AddCommand(opRetryCmd)
+
+	return opCmd
+}
@@ -1,40 +1,54 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"sort"
 
 	cac "github.com/aptible/cloud-api-clients/clients/go"
+	"github.com/aptible/cloud-cli/client"
 	"github.com/aptible/cloud-cli/config"
+	libkv "github.com/aptible/cloud-cli/lib/kv"
 	"github.com/aptible/cloud-cli/lib/org"
+	libresolve "github.com/aptible/cloud-cli/lib/resolve"
+	"github.com/aptible/cloud-cli/ui/common"
 	"github.com/aptible/cloud-cli/ui/fetch"
 	"github.com/aptible/cloud-cli/ui/form"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+type OrgOptions struct {
+	Name    string
+	Contact []string
+	Page    client.PageOptions
+	Clear   bool
+}
+
+var orgOptions = OrgOptions{}
+
 // organizationCreateRun - create an organization
 func organizationCreateRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
-		org := config.Vconfig.GetString("org")
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
 
-		formResult := form.FormResult{Org: org, Env: env}
-		err := liborg.OrgForm(config, &formResult)
+		contact, err := libkv.Parse(orgOptions.Contact)
 		if err != nil {
 			return err
 		}
 
-		output := make(map[string]interface{})
-		var ou string
 		params := cac.OrganizationInput{
 			Name:           args[0],
 			BaaStatus:      "pending",
-			AwsOu:          &ou,
-			ContactDetails: output,
+			ContactDetails: libkv.Merge(map[string]interface{}{}, contact),
 		}
 
-		progressModel := fetch.NewModel("creating organization", func() (interface{}, error) {
-			return config.Cc.CreateOrg(formResult.Org, params)
+		progressModel := fetch.NewModel(config.Ctx, "creating organization", func(ctx context.Context) (interface{}, error) {
+			return config.Cc.CreateOrg(ctx, params)
 		})
 		result, err := fetch.WithOutput(progressModel)
 		if err != nil {
@@ -50,27 +64,206 @@ func organizationCreateRun() config.CobraRunE {
 	}
 }
 
+// organizationUpdateRun - rename an organization or change its contact details
+func organizationUpdateRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+
+		contact, err := libkv.Parse(orgOptions.Contact)
+		if err != nil {
+			return err
+		}
+		if orgOptions.Name == "" && len(contact) == 0 {
+			return fmt.Errorf("nothing to update, pass --name or --contact")
+		}
+
+		formResult := form.FormResult{Org: config.Vconfig.GetString("org")}
+		err = liborg.OrgForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("updating organization %s", formResult.Org)
+		progressModel := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			// the api replaces the whole organization so start from what is there
+			org, err := config.Cc.FindOrg(ctx, formResult.Org)
+			if err != nil {
+				return nil, err
+			}
+
+			params := cac.OrganizationInput{
+				Name:           org.Name,
+				BaaStatus:      org.BaaStatus,
+				AwsOu:          org.AwsOu,
+				ContactDetails: libkv.Merge(org.ContactDetails, contact),
+			}
+			if orgOptions.Name != "" {
+				params.Name = orgOptions.Name
+			}
+			return config.Cc.UpdateOrg(ctx, formResult.Org, params)
+		})
+		result, err := fetch.WithOutput(progressModel)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(orgView(result.Result.(*cac.OrganizationOutput)))
+		return nil
+	}
+}
+
+// orgView - the organization along with its contact details
+func orgView(org *cac.OrganizationOutput) string {
+	s := liborg.OrgTable(org).View()
+
+	keys := make([]string, 0, len(org.ContactDetails))
+	for key := range org.ContactDetails {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	details := []string{"BAA Status", org.BaaStatus}
+	for _, key := range keys {
+		details = append(details, key, fmt.Sprint(org.ContactDetails[key]))
+	}
+	s += "\n" + common.KeyValueView(details...)
+	return s
+}
+
+// organizationShowRun - describe a single organization
+func organizationShowRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+
+		formResult := form.FormResult{Org: config.Vconfig.GetString("org")}
+		if len(args) > 0 {
+			formResult.Org = args[0]
+		}
+		err = liborg.OrgForm(config, &formResult)
+		if err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("describing organization %s", formResult.Org)
+		progressModel := fetch.NewModel(config.Ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return config.Cc.FindOrg(ctx, formResult.Org)
+		})
+		result, err := fetch.WithOutput(progressModel)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(orgView(result.Result.(*cac.OrganizationOutput)))
+		return nil
+	}
+}
+
 // orgListRun - lists all organizations
 func orgListRun() config.CobraRunE {
 	return func(cmd *cobra.Command, args []string) error {
-		config := config.NewCloudConfig(viper.GetViper())
-		progressModel := fetch.NewModel("fetching organizations", func() (interface{}, error) {
-			return config.Cc.ListOrgs()
+		if err := orgOptions.Page.Validate(); err != nil {
+			return err
+		}
+
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+		pager := config.Cc.OrgPages(orgOptions.Page)
+		count, err := fetch.Pages(config.Ctx, "fetching organizations", pager, func(orgs []cac.OrganizationOutput, first bool) {
+			// TODO - print with tea
+			if first {
+				fmt.Println("Organization(s) List")
+			}
+			fmt.Println(liborg.OrgTable(orgs).WithHeaderVisibility(first).View())
 		})
-		result, err := fetch.WithOutput(progressModel)
 		if err != nil {
 			return err
 		}
-		if result == nil {
+		if count == 0 {
 			fmt.Println("No organizations found.")
-			return nil
 		}
 
-		orgsTable := liborg.OrgTable(result.Result.([]cac.OrganizationOutput))
-		// TODO - print with tea
-		fmt.Println("Organization(s) List")
-		fmt.Println(orgsTable.View())
+		return nil
+	}
+}
+
+// saveDefault - stores a default in the config file, returns where it went
+// for messages
+func saveDefault(key string, value string) (string, error) {
+	filePath, err := config.SaveContextSetting(viper.GetViper(), key, value)
+	if err != nil {
+		return "", err
+	}
+	if name := config.ActiveContext(); name != "" {
+		return fmt.Sprintf("context %s", name), nil
+	}
+	return filePath, nil
+}
+
+// clearDefault - shared by org use --clear and env use --clear
+func clearDefault(key string, kind string) error {
+	found, err := config.ClearContextSetting(viper.GetViper(), key)
+	if err != nil {
+		return err
+	}
+	if !found {
+		fmt.Printf("No default %s to clear.\n", kind)
+		return nil
+	}
+	fmt.Printf("Cleared the default %s, forms no longer pre-select one.\n", kind)
+	return nil
+}
+
+// orgUseRun - stores the org forms start on
+func orgUseRun() config.CobraRunE {
+	return func(cmd *cobra.Command, args []string) error {
+		if orgOptions.Clear {
+			if len(args) > 0 {
+				return fmt.Errorf("--clear does not take an organization")
+			}
+			return clearDefault("default-org", "organization")
+		}
+		if len(args) == 0 {
+			return fmt.Errorf("pass the id or name of an organization, or --clear")
+		}
+
+		config, err := config.NewCloudConfig(cmd.Context(), viper.GetViper())
+		if err != nil {
+			return err
+		}
+		defer config.Cancel()
+		model := fetch.NewModel(config.Ctx, "fetching organizations", func(ctx context.Context) (interface{}, error) {
+			return config.Cc.ListOrgs(ctx)
+		})
+		result, err := fetch.WithOutput(model)
+		if err != nil {
+			return err
+		}
+
+		candidates := []libresolve.Candidate{}
+		for _, org := range result.Result.([]cac.OrganizationOutput) {
+			candidates = append(candidates, libresolve.Candidate{Id: org.Id, Name: org.Name})
+		}
+		org, err := libresolve.One("organization", args[0], candidates)
+		if err != nil {
+			return err
+		}
 
+		location, err := saveDefault("default-org", org.Id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Default organization is now %s in %s.\n", org, location)
 		return nil
 	}
 }
@@ -88,10 +281,28 @@ func NewOrgCmd() *cobra.Command {
 		Short:   "provision a new org.",
 		Long:    `The org create command will provision a new org.`,
 		Aliases: []string{"c"},
-		Args:    cobra.MinimumNArgs(1),
+		Args:    cobra.ExactArgs(1),
 		RunE:    organizationCreateRun(),
 	}
 
+	orgUpdateCmd := &cobra.Command{
+		Use:     "update",
+		Short:   "rename an org or change its contact details.",
+		Long:    `The org update command renames an org with --name or changes its contact details with --contact key=value, an empty value removes the detail.`,
+		Aliases: []string{"u", "edit"},
+		Args:    cobra.NoArgs,
+		RunE:    organizationUpdateRun(),
+	}
+
+	orgShowCmd := &cobra.Command{
+		Use:     "show [org_id|org_name]",
+		Short:   "show an org.",
+		Long:    `The org show command describes an org along with its contact details.`,
+		Aliases: []string{"describe"},
+		Args:    cobra.MaximumNArgs(1),
+		RunE:    organizationShowRun(),
+	}
+
 	orgListCmd := &cobra.Command{
 		Use:     "list",
 		Short:   "list all orgs you can access.",
@@ -100,8 +311,25 @@ func NewOrgCmd() *cobra.Command {
 		RunE:    orgListRun(),
 	}
 
+	orgUseCmd := &cobra.Command{
+		Use:   "use [org_id|org_name]",
+		Short: "pre-select an org in every org prompt.",
+		Long:  `The org use command stores the org that org prompts start on, marked "(current)".  It is kept in the current context when there is one.  --clear removes it, --org still skips the prompt.`,
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  orgUseRun(),
+	}
+
+	orgCreateCmd.Flags().StringArrayVarP(&orgOptions.Contact, "contact", "", []string{}, "contact detail as key=value, e.g. email=ops@example.com, can be repeated")
+	orgUpdateCmd.Flags().StringVarP(&orgOptions.Name, "name", "", "", "new name for the org")
+	orgUpdateCmd.Flags().StringArrayVarP(&orgOptions.Contact, "contact", "", []string{}, "contact detail as key=value, an empty value removes it, can be repeated")
+	config.AddPageFlags(orgListCmd, &orgOptions.Page)
+	orgUseCmd.Flags().BoolVarP(&orgOptions.Clear, "clear", "", false, "remove the default org")
+
 	orgCmd.AddCommand(orgCreateCmd)
+	orgCmd.AddCommand(orgUpdateCmd)
+	orgCmd.AddCommand(orgShowCmd)
 	orgCmd.AddCommand(orgListCmd)
+	orgCmd.AddCommand(orgUseCmd)
 
 	return orgCmd
 }
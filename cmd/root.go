@@ -1,26 +1,39 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"github.com/aptible/cloud-cli/client"
 	"github.com/aptible/cloud-cli/cmd/asset"
 	"github.com/aptible/cloud-cli/config"
+	libauth "github.com/aptible/cloud-cli/lib/auth"
 )
 
 var (
 	cfgFile    string
+	ctxName    string
 	token      string
 	authDomain string
 	apiDomain  string
+	apiScheme  string
 	org        string
 	env        string
 	debug      bool
+	debugFile  string
+	debugHar   string
+	timeout    time.Duration
+	offline    bool
+	noCache    bool
 )
 
 var logo = `
@@ -54,25 +67,47 @@ func NewRootCmd() *cobra.Command {
 		Use:   "aptible",
 		Short: "aptible is a command line interface to the Aptible.com platform.",
 		Long:  fmt.Sprintf("%s\n%s", logo, desc),
+		// errors are rendered by Execute so they can include api details
+		SilenceErrors: true,
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// flags and args parsed fine, any error from here on is not a usage problem
+			cmd.SilenceUsage = true
+		},
 	}
 
 	cobra.OnInitialize(initConfig())
+	config.PromptPassphrase = libauth.PromptPassphrase
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "common", "", "common file (default is $HOME/.aptible.yaml)")
+	rootCmd.PersistentFlags().StringVar(&ctxName, "context", "", "context from the config file to use instead of current-context")
 	rootCmd.PersistentFlags().StringVar(&token, "token", "", "jwt token")
 	rootCmd.PersistentFlags().StringVar(&authDomain, "auth-domain", "auth.aptible.com", "auth domain")
-	rootCmd.PersistentFlags().StringVar(&apiDomain, "api-domain", "cloud-api.cloud.aptible.com", "api domain")
-	rootCmd.PersistentFlags().StringVar(&org, "org", "", "organization id")
-	rootCmd.PersistentFlags().StringVar(&env, "env", "", "environment id")
+	rootCmd.PersistentFlags().StringVar(&apiDomain, "api-domain", "cloud-api.cloud.aptible.com", "api domain, or a full url such as http://localhost:8080")
+	rootCmd.PersistentFlags().StringVar(&apiScheme, "api-scheme", "https", "scheme used to reach --api-domain")
+	rootCmd.PersistentFlags().StringVar(&org, "org", "", "organization id or name")
+	rootCmd.PersistentFlags().StringVar(&env, "env", "", "environment id or name")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "debug logging")
+	rootCmd.PersistentFlags().StringVar(&debugFile, "debug-file", "", "write --debug output to this file instead of stderr")
+	rootCmd.PersistentFlags().StringVar(&debugHar, "debug-har", "", "record every api request and response to this HAR file, secrets are redacted")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "always fetch from the api instead of using cached lookups")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "use an in-memory fake of the cloud api, for demos and tests")
+	cobra.CheckErr(rootCmd.PersistentFlags().MarkHidden("offline"))
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "abort the command if it takes longer than this, e.g. 30s (default no timeout)")
 
 	errs := []error{
+		viper.BindPFlag("context", rootCmd.PersistentFlags().Lookup("context")),
 		viper.BindPFlag("token", rootCmd.PersistentFlags().Lookup("token")),
 		viper.BindPFlag("auth-domain", rootCmd.PersistentFlags().Lookup("auth-domain")),
 		viper.BindPFlag("api-domain", rootCmd.PersistentFlags().Lookup("api-domain")),
+		viper.BindPFlag("api-scheme", rootCmd.PersistentFlags().Lookup("api-scheme")),
 		viper.BindPFlag("org", rootCmd.PersistentFlags().Lookup("org")),
 		viper.BindPFlag("env", rootCmd.PersistentFlags().Lookup("env")),
 		viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug")),
+		viper.BindPFlag("debug-file", rootCmd.PersistentFlags().Lookup("debug-file")),
+		viper.BindPFlag("debug-har", rootCmd.PersistentFlags().Lookup("debug-har")),
+		viper.BindPFlag("no-cache", rootCmd.PersistentFlags().Lookup("no-cache")),
+		viper.BindPFlag("offline", rootCmd.PersistentFlags().Lookup("offline")),
+		viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout")),
 	}
 
 	viperErrOnInit := false
@@ -91,9 +126,17 @@ func NewRootCmd() *cobra.Command {
 	envCmd := NewEnvCmd()
 	dsCmd := asset.NewDatastoreCmd()
 	orgCmd := NewOrgCmd()
-	configCmd := config.NewConfigCmd()
+	configCmd := NewConfigCmd()
 	vpcCmd := asset.NewVPCCmd()
 	connCmd := NewConnectionCmd()
+	cacheCmd := NewCacheCmd()
+	inventoryCmd := asset.NewInventoryCmd()
+	opCmd := NewOperationCmd()
+	devCmd := NewDevCmd()
+	loginCmd := NewLoginCmd()
+	logoutCmd := NewLogoutCmd()
+	authCmd := NewAuthCmd()
+	contextCmd := NewContextCmd()
 
 	rootCmd.AddCommand(
 		assetCmd,
@@ -103,6 +146,14 @@ func NewRootCmd() *cobra.Command {
 		orgCmd,
 		vpcCmd,
 		connCmd,
+		cacheCmd,
+		inventoryCmd,
+		opCmd,
+		devCmd,
+		loginCmd,
+		logoutCmd,
+		authCmd,
+		contextCmd,
 	)
 
 	return rootCmd
@@ -110,10 +161,19 @@ func NewRootCmd() *cobra.Command {
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Errors are printed to stderr and mapped to an exit code, see client.ExitCode.
+// SIGINT and SIGTERM cancel the command's context, which aborts any in-flight
+// API request.
 func Execute(root *cobra.Command) {
-	err := root.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := root.ExecuteContext(ctx)
+	stop()
+	if stats := client.Stats(); viper.GetBool("debug") && stats.Requests > 0 {
+		stats.Report(os.Stderr)
+	}
 	if err != nil {
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, client.RenderError(err))
+		os.Exit(client.ExitCode(err))
 	}
 }
 
@@ -132,24 +192,31 @@ func initConfig() func() {
 			vconfig.SetConfigType("yaml")
 		}
 
+		// e.g. credential-store and token-expiry-warning, see config.Settings
+		config.SetDefaults(vconfig)
+
 		vconfig.AutomaticEnv()
 		vconfig.SetEnvPrefix("APTIBLE")
-		// transform viper/cobra keys with underscores for environment variables
-		replacer := strings.NewReplacer("-", "_")
+		// transform viper/cobra keys with underscores for environment variables,
+		// e.g. retry.max-attempts => APTIBLE_RETRY_MAX_ATTEMPTS
+		replacer := strings.NewReplacer("-", "_", ".", "_")
 		vconfig.SetEnvKeyReplacer(replacer)
 
 		if err := vconfig.ReadInConfig(); err == nil {
-			fmt.Println("Using common file:", vconfig.ConfigFileUsed())
+			// stderr so `aptible config get` can be used in scripts
+			fmt.Fprintln(os.Stderr, "Using common file:", vconfig.ConfigFileUsed())
 		}
 
-		token = vconfig.GetString("token")
-		if token == "" {
-			token, err = config.FindToken(home, fmt.Sprintf("https://%s", authDomain))
-			if err != nil {
-				fmt.Println("Unable to load token")
-				os.Exit(1)
-			}
-			vconfig.Set("token", token)
+		// the context's settings go in at the config file's level, below
+		// the flags and environment variables viper consults first
+		if err := config.ApplyContext(vconfig, os.Stderr); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
 		}
+
+		// the token is looked up by config.NewCloudConfig so commands that
+		// never talk to the api, like `cache clear`, work without one and
+		// the credential-helper only runs when a token is needed, see
+		// config.Token
 	}
 }
@@ -3,13 +3,17 @@ package config
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/aptible/cloud-cli/client"
+	"github.com/aptible/cloud-cli/client/cache"
+	"github.com/aptible/cloud-cli/client/fake"
 )
 
 /*
@@ -19,64 +23,264 @@ Core common for the Cloud API
 type CloudConfig struct {
 	Vconfig *viper.Viper
 	Cc      client.CloudClient
-	Ctx     context.Context
+	// Ctx - context every API call made for the current command should use
+	Ctx context.Context
+	// Cancel - aborts any in-flight request made with Ctx and closes the
+	// --debug-file, commands defer it so the timeout timer is released when
+	// they return
+	Cancel context.CancelFunc
 }
 
 // CobraRunE - alias for Cobra's RunE
 type CobraRunE func(cmd *cobra.Command, args []string) error
 
+// CacheDir - where cached api lookups are stored
+func CacheDir(home string) string {
+	return path.Join(home, ".aptible", "cache")
+}
+
+// tokensPath - where tokens are stored, shared with the vintage cli
+func tokensPath(home string) string {
+	return path.Join(home, ".aptible", "tokens.json")
+}
+
+// AuthURL - tokens.json is keyed by the full url of the auth domain,
+// e.g. https://auth.aptible.com
+func AuthURL(v *viper.Viper) string {
+	scheme, host := client.SplitAPIDomain(v.GetString("auth-domain"), "https")
+	return fmt.Sprintf("%s://%s", scheme, host)
+}
+
 // FindToken - tries to find an aptible token in various paths
 func FindToken(home string, domain string) (string, error) {
-	var tokenObj map[string]string
-	text, err := os.ReadFile(path.Join(home, ".aptible", "tokens.json"))
+	tokens, err := readTokens(home)
 	if err != nil {
 		return "", err
 	}
-	err = json.Unmarshal(text, &tokenObj)
+	return tokens[domain], nil
+}
+
+// readTokens - the tokens in tokens.json by domain, a missing file is
+// returned as is so callers can tell it apart with os.IsNotExist
+func readTokens(home string) (map[string]string, error) {
+	tokens := map[string]string{}
+	text, err := os.ReadFile(tokensPath(home))
+	if err != nil {
+		return tokens, err
+	}
+	if len(text) > 0 {
+		if err := json.Unmarshal(text, &tokens); err != nil {
+			return tokens, fmt.Errorf("unable to read %s: %w", tokensPath(home), err)
+		}
+	}
+	return tokens, nil
+}
+
+// updateTokens - rewrites tokens.json with fn applied, tokens for other
+// domains are kept as they are
+func updateTokens(home string, fn func(tokens map[string]string)) error {
+	tokens, err := readTokens(home)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	fn(tokens)
+
+	text, err := json.MarshalIndent(tokens, "", "  ")
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(tokensPath(home)), 0700); err != nil {
+		return err
 	}
+	return writeFileAtomic(tokensPath(home), text, 0600)
+}
 
-	return tokenObj[domain], nil
+// SaveToken - stores the token for domain in tokens.json
+func SaveToken(home string, domain string, token string) error {
+	return updateTokens(home, func(tokens map[string]string) {
+		tokens[domain] = token
+	})
 }
 
-func NewCloudConfig(v *viper.Viper) *CloudConfig {
+// RemoveToken - drops the token for domain from tokens.json, found reports
+// whether there was one
+func RemoveToken(home string, domain string) (bool, error) {
+	found := false
+	if _, err := os.Stat(tokensPath(home)); os.IsNotExist(err) {
+		return false, nil
+	}
+	err := updateTokens(home, func(tokens map[string]string) {
+		_, found = tokens[domain]
+		delete(tokens, domain)
+	})
+	return found, err
+}
+
+// NewCloudConfig - builds the config for a single command run.  The parent
+// context is usually the cobra command's context so that SIGINT/SIGTERM
+// cancels requests, and the `timeout` setting bounds the whole run.  A
+// missing or expired token is an unauthorized error, offline runs and
+// cassette replays never send one so they need none.
+func NewCloudConfig(parent context.Context, v *viper.Viper) (_ *CloudConfig, err error) {
 	host := v.GetString("api-domain")
-	token := v.GetString("token")
 	debug := v.GetBool("debug")
-	cc := client.NewClient(debug, host, token)
+	cassette, err := client.ParseCassetteMode(v.GetString("cassette"))
+	if err != nil {
+		return nil, err
+	}
+
+	if parent == nil {
+		parent = context.Background()
+	}
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+	)
+	if timeout := v.GetDuration("timeout"); timeout > 0 {
+		ctx, cancel = context.WithTimeout(parent, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(parent)
+	}
+	// the caller never gets Cancel when the config cannot be built
+	defer func() {
+		if err != nil {
+			cancel()
+		}
+	}()
+
+	var token string
+	if !v.GetBool("offline") && cassette != client.CassetteReplay {
+		// a credential-helper is bounded by the timeout like any request
+		token, _, err = Token(ctx, v)
+		if err != nil {
+			return nil, &client.APIError{
+				Kind:    client.KindUnauthorized,
+				Message: fmt.Sprintf("unable to load token: %s", err),
+			}
+		}
+		if token == "" {
+			return nil, &client.APIError{
+				Kind:    client.KindUnauthorized,
+				Message: "no token, run `aptible login` or pass --token",
+			}
+		}
+		// fail before any request rather than with a 401 from whichever comes first
+		if err := CheckTokenExpiry(os.Stderr, v, token, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	network := Network(v)
+	if _, err := network.Transport(); err != nil && !v.GetBool("offline") {
+		return nil, fmt.Errorf("invalid network settings: %w", err)
+	}
+	opts := []client.Option{
+		client.WithRetryPolicy(RetryPolicy(v)),
+		client.WithRateLimit(RateLimit(v)),
+		client.WithScheme(v.GetString("api-scheme")),
+		client.WithNetwork(network),
+	}
+	// without --debug nothing is logged, so the file is not even created
+	var debugOut *os.File
+	if debugFile := v.GetString("debug-file"); debugFile != "" && debug {
+		f, err := os.OpenFile(debugFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unable to open debug file, logging to stderr: %s\n", err)
+		} else {
+			debugOut = f
+			opts = append(opts, client.WithDebugOutput(f))
+		}
+	}
+	if harFile := v.GetString("debug-har"); harFile != "" {
+		opts = append(opts, client.WithHAR(harFile))
+	}
+	if cassette != client.CassetteOff {
+		opts = append(opts, client.WithCassette(cassette, v.GetString("cassette-file")))
+	}
+	var cc client.CloudClient
+	if v.GetBool("offline") {
+		offline := fake.NewSeeded()
+		// enough latency for spinners to show up during demos
+		offline.Latency = 300 * time.Millisecond
+		cc = offline
+	} else {
+		cc = client.NewClient(debug, host, token, opts...)
+		// cassettes must see every request to be deterministic
+		useCache := !v.GetBool("no-cache") && v.GetString("cassette") == ""
+		if home, err := os.UserHomeDir(); err == nil && useCache {
+			cc = cache.New(cc, CacheDir(home), cacheNamespace(host, token))
+		}
+	}
 
 	return &CloudConfig{
 		Vconfig: v,
 		Cc:      cc,
-	}
+		Ctx:     ctx,
+		Cancel: func() {
+			cancel()
+			if debugOut != nil {
+				debugOut.Close()
+			}
+		},
+	}, nil
 }
 
-func configCreateRun() CobraRunE {
-	return func(cmd *cobra.Command, args []string) error {
-		// TODO
-		return nil
+// cacheNamespace - cached lookups are shared by every token of the same
+// account, keying them on the token itself would start a new cache on every
+// login or short-lived credential-helper token and leave the old ones behind.
+// Tokens that are not JWTs carry no subject and fall back to the token.
+func cacheNamespace(host string, token string) string {
+	if claims, err := client.ParseClaims(token); err == nil && claims.Subject != "" {
+		return host + "\x00" + claims.Subject
 	}
+	return host + "\x00" + token
 }
 
-func NewConfigCmd() *cobra.Command {
-	configCmd := &cobra.Command{
-		Use:     "common",
-		Short:   "The common subcommand has assorted common utils associated with the CLI.",
-		Long:    "The common subcommand has assorted common utils associated with the CLI.",
-		Aliases: []string{"common", "c"},
+// RetryPolicy - reads the `retry.*` keys, anything unset keeps its default
+func RetryPolicy(v *viper.Viper) client.RetryPolicy {
+	policy := client.DefaultRetryPolicy()
+	if v.IsSet("retry.max-attempts") {
+		policy.MaxAttempts = v.GetInt("retry.max-attempts")
+	}
+	if v.IsSet("retry.base-delay") {
+		policy.BaseDelay = v.GetDuration("retry.base-delay")
+	}
+	if v.IsSet("retry.max-delay") {
+		policy.MaxDelay = v.GetDuration("retry.max-delay")
+	}
+	if v.IsSet("retry.non-idempotent") {
+		policy.RetryNonIdempotent = v.GetBool("retry.non-idempotent")
 	}
+	return policy
+}
 
-	configCreateCmd := &cobra.Command{
-		Use:     "create common",
-		Short:   "provision a fresh common file.",
-		Long:    "provision a fresh common file.",
-		Aliases: []string{"c"},
-		Args:    cobra.MinimumNArgs(0),
-		RunE:    configCreateRun(),
+// RateLimit - reads the `rate-limit.*` keys, anything unset keeps its default
+func RateLimit(v *viper.Viper) client.RateLimit {
+	limit := client.DefaultRateLimit()
+	if v.IsSet("rate-limit.requests-per-second") {
+		limit.RequestsPerSecond = v.GetFloat64("rate-limit.requests-per-second")
+	}
+	if v.IsSet("rate-limit.burst") {
+		limit.Burst = v.GetInt("rate-limit.burst")
 	}
+	return limit
+}
 
-	configCmd.AddCommand(configCreateCmd)
+// Network - reads the proxy and TLS keys shared by the api and auth clients
+func Network(v *viper.Viper) client.Network {
+	return client.Network{
+		Proxy:              v.GetString("proxy"),
+		CACert:             v.GetString("ca-cert"),
+		ClientCert:         v.GetString("client-cert"),
+		ClientKey:          v.GetString("client-key"),
+		InsecureSkipVerify: v.GetBool("insecure-skip-verify"),
+	}
+}
 
-	return configCmd
+// AddPageFlags - --limit, and the deprecated --page-size, for list commands
+func AddPageFlags(cmd *cobra.Command, opts *client.PageOptions) {
+	cmd.Flags().IntVarP(&opts.Limit, "limit", "", 0, "stop after this many items, 0 lists everything")
+	cmd.Flags().IntVarP(&opts.Size, "page-size", "", client.DefaultPageSize, "how many items to request from the api at a time")
+	// the api picks its own page size, only --offline still honors it
+	_ = cmd.Flags().MarkDeprecated("page-size", "the api picks its own page size")
 }
@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// ContextKeys - the settings `context create` copies from the flags given
+// to it, any other key can be added to a context by hand
+var ContextKeys = []string{"api-domain", "auth-domain", "org", "env"}
+
+var contextNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// activeContext - the context initConfig applied, empty when there is none
+var activeContext string
+
+// ActiveContext - the name of the context in use, e.g. to show in prompts
+func ActiveContext() string {
+	return activeContext
+}
+
+// ContextName - --context or APTIBLE_CONTEXT, else the file's current-context
+func ContextName(v *viper.Viper) (string, bool) {
+	if name := v.GetString("context"); name != "" {
+		return name, true
+	}
+	return v.GetString("current-context"), false
+}
+
+// ApplyContext - merges the settings of the active context over the top
+// level of the config file.  Viper still ranks flags and environment
+// variables above anything in the file, so --org beats the context's org.
+func ApplyContext(v *viper.Viper, w io.Writer) error {
+	activeContext = ""
+	name, explicit := ContextName(v)
+	if name == "" {
+		return nil
+	}
+
+	settings := v.GetStringMap("contexts." + name)
+	if !v.IsSet("contexts." + name) {
+		if !explicit {
+			// a stale current-context should not lock anyone out of `context use`
+			fmt.Fprintf(w, "current-context %q does not exist, ignoring it\n", name)
+			return nil
+		}
+		return fmt.Errorf("context %q does not exist, see `aptible context list`", name)
+	}
+
+	if err := v.MergeConfigMap(settings); err != nil {
+		return err
+	}
+	activeContext = name
+	return nil
+}
+
+// Context - a named set of settings in the config file
+type Context struct {
+	Name     string
+	Current  bool
+	Settings map[string]string
+}
+
+// Contexts - the contexts in f sorted by name, current is the one marked
+// as in use
+func Contexts(f *File, current string) []Context {
+	names := f.Keys("contexts")
+	sort.Strings(names)
+
+	contexts := []Context{}
+	for _, name := range names {
+		settings := map[string]string{}
+		for _, key := range f.Keys("contexts", name) {
+			node, _ := f.Get("contexts", name, key)
+			if node.Kind == yaml.ScalarNode {
+				settings[key] = node.Value
+			}
+		}
+		contexts = append(contexts, Context{
+			Name:     name,
+			Current:  name == current,
+			Settings: settings,
+		})
+	}
+	return contexts
+}
+
+// HasContext - whether f defines the context name
+func HasContext(f *File, name string) bool {
+	_, ok := f.Get("contexts", name)
+	return ok
+}
+
+// ValidateContextName - names end up in viper keys, which are lower cased
+// and split on dots
+func ValidateContextName(name string) error {
+	if !contextNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid context name %q, use lower case letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// contextKeys - where key lives, inside the active context when there is one
+// so production and staging keep their own value
+func contextKeys(key string) []string {
+	keys := strings.Split(key, ".")
+	if name := ActiveContext(); name != "" {
+		return append([]string{"contexts", name}, keys...)
+	}
+	return keys
+}
+
+// SaveContextSetting - writes key to the config file, in the active context
+// when there is one.  The file's path is returned for messages.
+func SaveContextSetting(v *viper.Viper, key string, value interface{}) (string, error) {
+	filePath, err := ConfigFilePath(v)
+	if err != nil {
+		return "", err
+	}
+	f, err := LoadFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	if err := f.Set(value, contextKeys(key)...); err != nil {
+		return "", err
+	}
+	return f.Path, f.Save()
+}
+
+// ClearContextSetting - removes key from where SaveContextSetting writes it,
+// found reports whether it was set there
+func ClearContextSetting(v *viper.Viper, key string) (bool, error) {
+	filePath, err := ConfigFilePath(v)
+	if err != nil {
+		return false, err
+	}
+	f, err := LoadFile(filePath)
+	if err != nil {
+		return false, err
+	}
+	if !f.Unset(contextKeys(key)...) {
+		return false, nil
+	}
+	return true, f.Save()
+}
@@ -0,0 +1,202 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"github.com/spf13/viper"
+)
+
+// Credential stores selectable with the `credential-store` key
+const (
+	// CredentialStoreAuto - the keyring when one is available, else the encrypted file
+	CredentialStoreAuto = "auto"
+	// CredentialStoreKeyring - Secret Service on linux, the login keychain on macOS
+	CredentialStoreKeyring = "keyring"
+	// CredentialStoreEncryptedFile - ~/.aptible/tokens.enc, encrypted with a passphrase
+	CredentialStoreEncryptedFile = "encrypted-file"
+	// CredentialStoreFile - plaintext ~/.aptible/tokens.json, shared with the vintage cli
+	CredentialStoreFile = "file"
+)
+
+// CredentialStore - where tokens are kept, keyed by the auth domain url
+type CredentialStore interface {
+	// Name - describes the store, e.g. for auth status
+	Name() string
+	// Get - the token for domain, empty when there is none
+	Get(domain string) (string, error)
+	Set(domain string, token string) error
+	// Delete - found reports whether there was a token to delete
+	Delete(domain string) (bool, error)
+}
+
+// PromptPassphrase - asks for the passphrase of the encrypted file, confirm
+// is set when the file is about to be created.  The config package cannot
+// depend on the ui so the cmd package provides it.
+var PromptPassphrase func(confirm bool) (string, error)
+
+// NewCredentialStore - the store picked by the `credential-store` key
+func NewCredentialStore(v *viper.Viper, home string) (CredentialStore, error) {
+	return newCredentialStore(v, home, func(confirm bool) (string, error) {
+		if PromptPassphrase == nil {
+			return "", fmt.Errorf("set APTIBLE_CREDENTIAL_PASSPHRASE to unlock %s", encryptedTokensPath(home))
+		}
+		return PromptPassphrase(confirm)
+	})
+}
+
+// HelperCacheStore - where tokens from the credential-helper are cached: the
+// credential store, but never the plaintext file and without prompting for
+// the passphrase, so nil unless a token can be kept safely and silently
+func HelperCacheStore(v *viper.Viper, home string) CredentialStore {
+	store, err := newCredentialStore(v, home, func(confirm bool) (string, error) {
+		return "", fmt.Errorf("APTIBLE_CREDENTIAL_PASSPHRASE is not set")
+	})
+	if err != nil {
+		return nil
+	}
+	if _, ok := store.(*fileStore); ok {
+		return nil
+	}
+	return store
+}
+
+// newCredentialStore - prompt is asked for the passphrase of the encrypted
+// file when APTIBLE_CREDENTIAL_PASSPHRASE is not set
+func newCredentialStore(v *viper.Viper, home string, prompt func(confirm bool) (string, error)) (CredentialStore, error) {
+	kind := v.GetString("credential-store")
+	if kind == "" || kind == CredentialStoreAuto {
+		kind = CredentialStoreEncryptedFile
+		if keyringAvailable() {
+			kind = CredentialStoreKeyring
+		}
+	}
+
+	switch kind {
+	case CredentialStoreKeyring:
+		return newKeyringStore()
+	case CredentialStoreEncryptedFile:
+		return newEncryptedFileStore(encryptedTokensPath(home), func(confirm bool) (string, error) {
+			// only ever from the environment, a passphrase in the config file
+			// would sit in plaintext next to the file it protects
+			if passphrase := os.Getenv("APTIBLE_CREDENTIAL_PASSPHRASE"); passphrase != "" {
+				return passphrase, nil
+			}
+			return prompt(confirm)
+		}), nil
+	case CredentialStoreFile:
+		return &fileStore{home: home}, nil
+	default:
+		return nil, fmt.Errorf(
+			"unknown credential-store %q, expected one of %s, %s, %s or %s",
+			kind, CredentialStoreAuto, CredentialStoreKeyring, CredentialStoreEncryptedFile, CredentialStoreFile,
+		)
+	}
+}
+
+// OpenCredentialStore - the configured store with any plaintext tokens copied into it
+func OpenCredentialStore(v *viper.Viper) (CredentialStore, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	store, err := NewCredentialStore(v, home)
+	if err != nil {
+		return nil, err
+	}
+	if err := MigrateTokens(home, store, os.Stderr); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// migratedTokensPath - the tokens.json tokens already copied into another
+// store, by domain.  Only hashes are kept so the file holds no secrets.
+func migratedTokensPath(home string) string {
+	return path.Join(home, ".aptible", "tokens.migrated.json")
+}
+
+// MigrateTokens - copies the tokens in the plaintext tokens.json into store,
+// a no-op when store is the plaintext file itself or there is nothing new to
+// copy.  The file is left in place since the vintage cli still reads it, the
+// tokens copied are recorded so a token is only copied again once the
+// vintage cli has replaced it.
+func MigrateTokens(home string, store CredentialStore, w io.Writer) error {
+	if _, ok := store.(*fileStore); ok {
+		return nil
+	}
+
+	tokens, err := readTokens(home)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to migrate tokens: %w", err)
+	}
+
+	// a missing or unreadable record only means copying everything again
+	migrated := map[string]string{}
+	if text, err := os.ReadFile(migratedTokensPath(home)); err == nil {
+		_ = json.Unmarshal(text, &migrated)
+	}
+
+	copied := 0
+	for domain, token := range tokens {
+		sum := sha256.Sum256([]byte(token))
+		hash := hex.EncodeToString(sum[:])
+		if token == "" || migrated[domain] == hash {
+			continue
+		}
+		if err := store.Set(domain, token); err != nil {
+			return fmt.Errorf("unable to migrate %s: %w", tokensPath(home), err)
+		}
+		migrated[domain] = hash
+		copied++
+	}
+	if copied == 0 {
+		return nil
+	}
+
+	text, err := json.MarshalIndent(migrated, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(migratedTokensPath(home), text, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(
+		w, "Copied %d token(s) from %s to %s. It is left in place for the vintage Aptible CLI, delete it once you no longer use that CLI.\n",
+		copied, tokensPath(home), store.Name(),
+	)
+	return nil
+}
+
+// fileStore - the plaintext tokens.json the vintage cli reads and writes
+type fileStore struct {
+	home string
+}
+
+func (s *fileStore) Name() string {
+	return tokensPath(s.home)
+}
+
+func (s *fileStore) Get(domain string) (string, error) {
+	token, err := FindToken(s.home, domain)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return token, err
+}
+
+func (s *fileStore) Set(domain string, token string) error {
+	return SaveToken(s.home, domain, token)
+}
+
+func (s *fileStore) Delete(domain string) (bool, error) {
+	return RemoveToken(s.home, domain)
+}
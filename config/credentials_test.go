@@ -0,0 +1,114 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestMigrateTokens(t *testing.T) {
+	const (
+		prod    = "https://auth.example.com"
+		staging = "https://auth.staging.example.com"
+	)
+	home := t.TempDir()
+	if err := SaveToken(home, prod, "token-prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveToken(home, staging, "token-staging"); err != nil {
+		t.Fatal(err)
+	}
+	store := memoryStore{}
+
+	var out bytes.Buffer
+	if err := MigrateTokens(home, store, &out); err != nil {
+		t.Fatal(err)
+	}
+	if store[prod] != "token-prod" || store[staging] != "token-staging" {
+		t.Errorf("store = %v, want both tokens", store)
+	}
+	if !strings.Contains(out.String(), "Copied 2 token(s)") || !strings.Contains(out.String(), "vintage Aptible CLI") {
+		t.Errorf("output = %q", out.String())
+	}
+	// the vintage cli still reads the file
+	if token, err := FindToken(home, prod); err != nil || token != "token-prod" {
+		t.Errorf("tokens.json has %q, %v after the migration", token, err)
+	}
+	record, err := os.ReadFile(migratedTokensPath(home))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(record), "token-") {
+		t.Errorf("the migration record holds a token: %s", record)
+	}
+
+	// nothing new, nothing copied, even after the token is logged out of
+	delete(store, prod)
+	out.Reset()
+	if err := MigrateTokens(home, store, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 || store[prod] != "" {
+		t.Errorf("migrated again: %q, store = %v", out.String(), store)
+	}
+
+	// the vintage cli logged in again
+	if err := SaveToken(home, staging, "token-staging-2"); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := MigrateTokens(home, store, &out); err != nil {
+		t.Fatal(err)
+	}
+	if store[staging] != "token-staging-2" || store[prod] != "" {
+		t.Errorf("store = %v, want only the new staging token copied", store)
+	}
+	if !strings.Contains(out.String(), "Copied 1 token(s)") {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestMigrateTokensNoop(t *testing.T) {
+	home := t.TempDir()
+	var out bytes.Buffer
+
+	// no tokens.json
+	if err := MigrateTokens(home, memoryStore{}, &out); err != nil {
+		t.Fatal(err)
+	}
+	// the store is tokens.json
+	if err := SaveToken(home, "https://auth.example.com", "token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := MigrateTokens(home, &fileStore{home: home}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("output = %q", out.String())
+	}
+	if _, err := os.Stat(migratedTokensPath(home)); !os.IsNotExist(err) {
+		t.Errorf("a migration was recorded: %v", err)
+	}
+}
+
+func TestMalformedTokens(t *testing.T) {
+	home := t.TempDir()
+	if err := os.MkdirAll(path.Dir(tokensPath(home)), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tokensPath(home), []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := FindToken(home, "https://auth.example.com"); err == nil || !strings.Contains(err.Error(), tokensPath(home)) {
+		t.Errorf("FindToken err = %v, want it to name the file", err)
+	}
+	if _, err := (&fileStore{home: home}).Get("https://auth.example.com"); err == nil {
+		t.Error("fileStore.Get succeeded")
+	}
+	if err := MigrateTokens(home, memoryStore{}, &bytes.Buffer{}); err == nil {
+		t.Error("MigrateTokens succeeded")
+	}
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+)
+
+const (
+	encryptedVersion = 1
+	// pbkdf2Iterations - OWASP's 2023 recommendation for PBKDF2-HMAC-SHA256
+	pbkdf2Iterations = 600000
+	// maxPbkdf2Iterations - a corrupt or tampered file asking for more than
+	// this would stall every command deriving the key
+	maxPbkdf2Iterations = 10 * pbkdf2Iterations
+	saltSize            = 16
+	keySize             = 32
+)
+
+// encryptedTokensPath - where the encrypted-file store keeps tokens
+func encryptedTokensPath(home string) string {
+	return path.Join(home, ".aptible", "tokens.enc")
+}
+
+// encryptedFile - on disk format, the plaintext is the same domain => token
+// map as tokens.json sealed with AES-256-GCM under a PBKDF2 derived key
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// encryptedFileStore - for machines without a keyring, e.g. headless linux
+type encryptedFileStore struct {
+	path       string
+	passphrase func(confirm bool) (string, error)
+
+	// the passphrase is asked for at most once per command
+	unlocked string
+}
+
+func newEncryptedFileStore(path string, passphrase func(confirm bool) (string, error)) *encryptedFileStore {
+	return &encryptedFileStore{path: path, passphrase: passphrase}
+}
+
+func (s *encryptedFileStore) Name() string {
+	return s.path
+}
+
+func (s *encryptedFileStore) unlock(confirm bool) (string, error) {
+	if s.unlocked != "" {
+		return s.unlocked, nil
+	}
+	passphrase, err := s.passphrase(confirm)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("a passphrase is required to unlock %s", s.path)
+	}
+	s.unlocked = passphrase
+	return passphrase, nil
+}
+
+// read - the stored tokens, an empty map without asking for the passphrase
+// when the file does not exist yet
+func (s *encryptedFileStore) read() (map[string]string, error) {
+	text, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(text, &file); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", s.path, err)
+	}
+	if file.Version != encryptedVersion || file.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("%s uses an unsupported format, version %d with %s", s.path, file.Version, file.KDF)
+	}
+	if file.Iterations > maxPbkdf2Iterations {
+		return nil, fmt.Errorf("%s asks for %d key derivation iterations, more than the %d allowed", s.path, file.Iterations, maxPbkdf2Iterations)
+	}
+
+	passphrase, err := s.unlock(false)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, file.Salt, file.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		s.unlocked = ""
+		return nil, fmt.Errorf("unable to decrypt %s, is the passphrase right?", s.path)
+	}
+
+	tokens := map[string]string{}
+	if err := json.Unmarshal(plaintext, &tokens); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", s.path, err)
+	}
+	return tokens, nil
+}
+
+// write - seals tokens with a fresh salt and nonce
+func (s *encryptedFileStore) write(tokens map[string]string) error {
+	_, statErr := os.Stat(s.path)
+	passphrase, err := s.unlock(os.IsNotExist(statErr))
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	gcm, err := newGCM(passphrase, salt, pbkdf2Iterations)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	text, err := json.MarshalIndent(encryptedFile{
+		Version:    encryptedVersion,
+		KDF:        "pbkdf2-sha256",
+		Iterations: pbkdf2Iterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, text, 0600)
+}
+
+func (s *encryptedFileStore) Get(domain string) (string, error) {
+	tokens, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	return tokens[domain], nil
+}
+
+func (s *encryptedFileStore) Set(domain string, token string) error {
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[domain] = token
+	return s.write(tokens)
+}
+
+func (s *encryptedFileStore) Delete(domain string) (bool, error) {
+	tokens, err := s.read()
+	if err != nil {
+		return false, err
+	}
+	if _, ok := tokens[domain]; !ok {
+		return false, nil
+	}
+	delete(tokens, domain)
+	return true, s.write(tokens)
+}
+
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if iterations < 1 || len(salt) == 0 {
+		return nil, fmt.Errorf("invalid key derivation parameters")
+	}
+	block, err := aes.NewCipher(pbkdf2SHA256([]byte(passphrase), salt, iterations, keySize))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 - RFC 8018 PBKDF2 with HMAC-SHA256, golang.org/x/crypto is
+// not a dependency so it is spelled out here
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	key := make([]byte, 0, blocks*hashLen)
+	var counter [4]byte
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		u := prf.Sum(nil)
+
+		t := make([]byte, len(u))
+		copy(t, u)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
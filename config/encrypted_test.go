@@ -0,0 +1,143 @@
+package config
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestPBKDF2SHA256(t *testing.T) {
+	// RFC 7914 section 11
+	tests := []struct {
+		password   string
+		salt       string
+		iterations int
+		want       string
+	}{
+		{
+			password:   "passwd",
+			salt:       "salt",
+			iterations: 1,
+			want:       "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		},
+		{
+			password:   "Password",
+			salt:       "NaCl",
+			iterations: 80000,
+			want:       "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d",
+		},
+	}
+	for _, tt := range tests {
+		got := hex.EncodeToString(pbkdf2SHA256([]byte(tt.password), []byte(tt.salt), tt.iterations, 64))
+		if got != tt.want {
+			t.Errorf("pbkdf2SHA256(%q, %q, %d) = %s, want %s", tt.password, tt.salt, tt.iterations, got, tt.want)
+		}
+	}
+
+	// shorter keys are a prefix of longer ones
+	short := hex.EncodeToString(pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, keySize))
+	if short != tests[0].want[:2*keySize] {
+		t.Errorf("32 byte key = %s, want %s", short, tests[0].want[:2*keySize])
+	}
+}
+
+func passphrase(p string) func(bool) (string, error) {
+	return func(bool) (string, error) { return p, nil }
+}
+
+func TestEncryptedFileStoreRoundTrip(t *testing.T) {
+	file := path.Join(t.TempDir(), "tokens.enc")
+	store := newEncryptedFileStore(file, passphrase("correct horse"))
+	if err := store.Set("https://auth.example.com", "token-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("https://auth.staging.example.com", "token-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(text), "token-1") {
+		t.Errorf("%s holds the token in plaintext", file)
+	}
+	if info, _ := os.Stat(file); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	// a fresh store has to derive the key from the file again
+	reopened := newEncryptedFileStore(file, passphrase("correct horse"))
+	for domain, want := range map[string]string{
+		"https://auth.example.com":         "token-1",
+		"https://auth.staging.example.com": "token-2",
+		"https://auth.other.example.com":   "",
+	} {
+		got, err := reopened.Get(domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Get(%q) = %q, want %q", domain, got, want)
+		}
+	}
+
+	found, err := reopened.Delete("https://auth.example.com")
+	if err != nil || !found {
+		t.Fatalf("Delete = %v, %v, want true, nil", found, err)
+	}
+	if got, _ := reopened.Get("https://auth.example.com"); got != "" {
+		t.Errorf("Get after Delete = %q, want nothing", got)
+	}
+}
+
+func TestEncryptedFileStoreWrongPassphrase(t *testing.T) {
+	file := path.Join(t.TempDir(), "tokens.enc")
+	if err := newEncryptedFileStore(file, passphrase("correct horse")).Set("https://auth.example.com", "token"); err != nil {
+		t.Fatal(err)
+	}
+
+	store := newEncryptedFileStore(file, passphrase("battery staple"))
+	if _, err := store.Get("https://auth.example.com"); err == nil || !strings.Contains(err.Error(), "passphrase") {
+		t.Errorf("Get with the wrong passphrase = %v, want a passphrase error", err)
+	}
+	// a wrong passphrase must not be kept for the next attempt
+	if store.unlocked != "" {
+		t.Errorf("the wrong passphrase was kept")
+	}
+}
+
+func TestEncryptedFileStoreIterationBound(t *testing.T) {
+	file := path.Join(t.TempDir(), "tokens.enc")
+	if err := newEncryptedFileStore(file, passphrase("correct horse")).Set("https://auth.example.com", "token"); err != nil {
+		t.Fatal(err)
+	}
+	text, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var encrypted encryptedFile
+	if err := json.Unmarshal(text, &encrypted); err != nil {
+		t.Fatal(err)
+	}
+	encrypted.Iterations = 1 << 40
+	text, _ = json.Marshal(encrypted)
+	if err := os.WriteFile(file, text, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	asked := false
+	store := newEncryptedFileStore(file, func(bool) (string, error) {
+		asked = true
+		return "correct horse", nil
+	})
+	if _, err := store.Get("https://auth.example.com"); err == nil || !strings.Contains(err.Error(), "iterations") {
+		t.Errorf("Get = %v, want an iterations error", err)
+	}
+	if asked {
+		t.Errorf("the passphrase was asked for before the file was checked")
+	}
+}
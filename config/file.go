@@ -0,0 +1,251 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile - the file written when none exists yet
+func DefaultConfigFile(home string) string {
+	return path.Join(home, ".aptible.yaml")
+}
+
+// ConfigFilePath - the file viper read, or the default one when there was none
+func ConfigFilePath(v *viper.Viper) (string, error) {
+	if used := v.ConfigFileUsed(); used != "" {
+		return used, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return DefaultConfigFile(home), nil
+}
+
+// File - the yaml config file, edited in place so comments and keys the cli
+// does not know about survive every write
+type File struct {
+	Path string
+	doc  *yaml.Node
+}
+
+// LoadFile - a missing file is treated as an empty one
+func LoadFile(filePath string) (*File, error) {
+	f := &File{
+		Path: filePath,
+		doc: &yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{{Kind: yaml.MappingNode}},
+		},
+	}
+
+	text, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(text)) == 0 {
+		return f, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(text, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", filePath, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("unable to parse %s: expected a mapping at the top level", filePath)
+	}
+	f.doc = &doc
+	return f, nil
+}
+
+func (f *File) root() *yaml.Node {
+	return f.doc.Content[0]
+}
+
+// lookup - the value node of key inside mapping, nil when missing
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// Get - the node at keys, e.g. Get("contexts", "staging")
+func (f *File) Get(keys ...string) (*yaml.Node, bool) {
+	node := f.root()
+	for _, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil, false
+		}
+		node = lookup(node, key)
+		if node == nil {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// GetString - the scalar at keys, empty when missing
+func (f *File) GetString(keys ...string) string {
+	node, ok := f.Get(keys...)
+	if !ok || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+// Keys - the keys of the mapping at keys in file order
+func (f *File) Keys(keys ...string) []string {
+	node, ok := f.Get(keys...)
+	if !ok || node.Kind != yaml.MappingNode {
+		return []string{}
+	}
+	names := []string{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		names = append(names, node.Content[i].Value)
+	}
+	return names
+}
+
+// Leaves - the dotted paths of every value that is not a mapping, e.g.
+// retry.max-attempts, skipping the top level keys in skip
+func (f *File) Leaves(skip ...string) []string {
+	skipped := map[string]bool{}
+	for _, key := range skip {
+		skipped[key] = true
+	}
+	leaves := []string{}
+	var walk func(node *yaml.Node, prefix string)
+	walk = func(node *yaml.Node, prefix string) {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := prefix + node.Content[i].Value
+			if prefix == "" && skipped[key] {
+				continue
+			}
+			if value := node.Content[i+1]; value.Kind == yaml.MappingNode {
+				walk(value, key+".")
+			} else {
+				leaves = append(leaves, key)
+			}
+		}
+	}
+	walk(f.root(), "")
+	return leaves
+}
+
+// Set - stores value at keys, creating the mappings on the way
+func (f *File) Set(value interface{}, keys ...string) error {
+	if len(keys) == 0 {
+		return errors.New("no key given")
+	}
+
+	var encoded yaml.Node
+	if err := encoded.Encode(value); err != nil {
+		return err
+	}
+
+	node := f.root()
+	for i, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s is not a mapping", keys[i-1])
+		}
+		next := lookup(node, key)
+		last := i == len(keys)-1
+		if next == nil {
+			next = &yaml.Node{Kind: yaml.MappingNode}
+			if last {
+				next = &encoded
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, next)
+		} else if last {
+			// keep the comments attached to the old value
+			encoded.HeadComment = next.HeadComment
+			encoded.LineComment = next.LineComment
+			encoded.FootComment = next.FootComment
+			*next = encoded
+		}
+		node = next
+	}
+	return nil
+}
+
+// Unset - removes keys, found reports whether it was there
+func (f *File) Unset(keys ...string) bool {
+	if len(keys) == 0 {
+		return false
+	}
+	parent, ok := f.Get(keys[:len(keys)-1]...)
+	if !ok || parent.Kind != yaml.MappingNode {
+		return false
+	}
+	key := keys[len(keys)-1]
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == key {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Save - writes the file, readable only by the user since it may hold a token
+func (f *File) Save() error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(f.doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(path.Dir(f.Path), 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(f.Path, buf.Bytes(), 0600)
+}
+
+// writeFileAtomic - writes a temporary file next to filePath and renames it
+// over filePath, so a crash or a full disk leaves the old file intact rather
+// than a truncated one.  perm applies to existing files too, and a symlink is
+// followed so the file it points at is replaced rather than the link.
+func writeFileAtomic(filePath string, data []byte, perm os.FileMode) error {
+	if target, err := filepath.EvalSymlinks(filePath); err == nil {
+		filePath = target
+	}
+	tmp, err := os.CreateTemp(path.Dir(filePath), "."+path.Base(filePath)+".*")
+	if err != nil {
+		return err
+	}
+	// a no-op once the rename went through
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filePath)
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleConfig = `# managed by hand, keep this comment
+api-domain: api.example.com # the production api
+org: acme
+retry:
+  # be patient with the api
+  max-attempts: 5
+  base-delay: 1s
+contexts:
+  staging:
+    api-domain: api.staging.example.com
+# not a setting the cli knows
+x-team: platform
+`
+
+func TestFileRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		edit  func(f *File) error
+		want  map[string]string
+		gone  [][]string
+		lines []string
+	}{
+		{
+			name: "set existing",
+			edit: func(f *File) error { return f.Set("api.other.example.com", "api-domain") },
+			want: map[string]string{"api-domain": "api.other.example.com"},
+			// the comment on the old value stays with the new one
+			lines: []string{"api-domain: api.other.example.com # the production api"},
+		},
+		{
+			name: "set nested",
+			edit: func(f *File) error { return f.Set(7, "retry", "max-attempts") },
+			want: map[string]string{"retry.max-attempts": "7", "retry.base-delay": "1s"},
+		},
+		{
+			name: "set creates mappings",
+			edit: func(f *File) error { return f.Set("acme-prod", "contexts", "prod", "org") },
+			want: map[string]string{"contexts.prod.org": "acme-prod", "contexts.staging.api-domain": "api.staging.example.com"},
+		},
+		{
+			name: "unset",
+			edit: func(f *File) error {
+				if !f.Unset("org") {
+					t.Error("Unset(org) found nothing")
+				}
+				if f.Unset("no-such-key") {
+					t.Error("Unset(no-such-key) found something")
+				}
+				return nil
+			},
+			gone: [][]string{{"org"}},
+		},
+		{
+			name: "unset nested",
+			edit: func(f *File) error {
+				f.Unset("retry", "base-delay")
+				return nil
+			},
+			want: map[string]string{"retry.max-attempts": "5"},
+			gone: [][]string{{"retry", "base-delay"}},
+		},
+		{
+			name: "set below a scalar",
+			edit: func(f *File) error {
+				if err := f.Set("x", "org", "name"); err == nil {
+					t.Error("setting below a scalar succeeded")
+				}
+				return nil
+			},
+			want: map[string]string{"org": "acme"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := path.Join(t.TempDir(), ".aptible.yaml")
+			if err := os.WriteFile(file, []byte(sampleConfig), 0644); err != nil {
+				t.Fatal(err)
+			}
+			f, err := LoadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.edit(f); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.Save(); err != nil {
+				t.Fatal(err)
+			}
+
+			saved, err := LoadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, want := range tt.want {
+				if got := saved.GetString(strings.Split(key, ".")...); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+			for _, keys := range tt.gone {
+				if _, ok := saved.Get(keys...); ok {
+					t.Errorf("%s is still set", strings.Join(keys, "."))
+				}
+			}
+
+			text, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range append([]string{
+				"# managed by hand, keep this comment",
+				"# be patient with the api",
+				"# not a setting the cli knows",
+				"x-team: platform",
+			}, tt.lines...) {
+				if !strings.Contains(string(text), line) {
+					t.Errorf("saved file lost %q:\n%s", line, text)
+				}
+			}
+
+			info, err := os.Stat(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != 0600 {
+				t.Errorf("saved with mode %o, want 600", info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents *string
+		wantErr  bool
+	}{
+		{name: "missing"},
+		{name: "empty", contents: strPtr("")},
+		{name: "whitespace", contents: strPtr("\n  \n")},
+		{name: "not a mapping", contents: strPtr("- a\n- b\n"), wantErr: true},
+		{name: "not yaml", contents: strPtr("a: [b\n"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := path.Join(t.TempDir(), ".aptible.yaml")
+			if tt.contents != nil {
+				if err := os.WriteFile(file, []byte(*tt.contents), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			f, err := LoadFile(file)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("LoadFile succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if keys := f.Keys(); len(keys) != 0 {
+				t.Errorf("keys = %v, want none", keys)
+			}
+
+			// an empty file can be written to like any other
+			if err := f.Set("acme", "org"); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.Save(); err != nil {
+				t.Fatal(err)
+			}
+			saved, err := LoadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := saved.GetString("org"); got != "acme" {
+				t.Errorf("org = %q, want acme", got)
+			}
+		})
+	}
+}
+
+func TestFileLeaves(t *testing.T) {
+	file := path.Join(t.TempDir(), ".aptible.yaml")
+	if err := os.WriteFile(file, []byte(sampleConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := LoadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"api-domain", "org", "retry.max-attempts", "retry.base-delay", "x-team"}
+	if got := f.Leaves("contexts"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Leaves = %v, want %v", got, want)
+	}
+	if got := f.Keys("contexts"); !reflect.DeepEqual(got, []string{"staging"}) {
+		t.Errorf("Keys(contexts) = %v", got)
+	}
+}
+
+func TestSaveFollowsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	target := path.Join(dir, "dotfiles", "aptible.yaml")
+	if err := os.MkdirAll(path.Dir(target), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte(sampleConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	link := path.Join(dir, ".aptible.yaml")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := LoadFile(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Set("acme-2", "org"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("%s is no longer a symlink", link)
+	}
+	saved, err := LoadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.GetString("org"); got != "acme-2" {
+		t.Errorf("org = %q in the link target, want acme-2", got)
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+const (
+	// helperTimeout - how long a credential helper may take, e.g. to reach vault
+	helperTimeout = 30 * time.Second
+	// helperExpiryMargin - cached tokens are dropped this long before they
+	// expire so one does not run out in the middle of a command
+	helperExpiryMargin = time.Minute
+)
+
+// HelperToken - what a credential helper prints on stdout
+type HelperToken struct {
+	Token string `json:"token"`
+	// ExpiresAt - RFC 3339, optional for JWTs which carry their own expiry
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CredentialHelper - an executable that hands out tokens, in the style of
+// git and docker credential helpers.  It is run with the argument `get`, is
+// given the auth domain url on stdin and prints a HelperToken as JSON.
+type CredentialHelper struct {
+	// Command - the `credential-helper` setting, a bare name like `vault`
+	// runs aptible-credential-vault when that is on the PATH
+	Command string
+	// Store - keeps tokens between commands, nil keeps them for this
+	// process only, see HelperCacheStore
+	Store CredentialStore
+	Now   func() time.Time
+}
+
+// helperTokens - tokens handed out during this process, by cache key
+var (
+	helperTokens   = map[string]HelperToken{}
+	helperTokensMu sync.Mutex
+)
+
+// NewCredentialHelper - the helper for command, tokens are cached in store
+func NewCredentialHelper(command string, store CredentialStore) *CredentialHelper {
+	return &CredentialHelper{
+		Command: command,
+		Store:   store,
+		Now:     time.Now,
+	}
+}
+
+// Name - describes the helper, e.g. for auth status
+func (h *CredentialHelper) Name() string {
+	return fmt.Sprintf("credential-helper %s", h.Command)
+}
+
+// argv - the program and its arguments, the setting is split on spaces
+// rather than handed to a shell
+func (h *CredentialHelper) argv() ([]string, error) {
+	fields := strings.Fields(h.Command)
+	if len(fields) == 0 {
+		return nil, errors.New("credential-helper is empty")
+	}
+	if !strings.ContainsRune(fields[0], os.PathSeparator) {
+		if named, err := exec.LookPath("aptible-credential-" + fields[0]); err == nil {
+			fields[0] = named
+		}
+	}
+	return append(fields, "get"), nil
+}
+
+// cacheKey - tokens are kept next to the ones from `aptible login`, under
+// a key that no auth domain url can clash with
+func (h *CredentialHelper) cacheKey(domain string) string {
+	sum := sha256.Sum256([]byte(h.Command + "\x00" + domain))
+	return "credential-helper:" + hex.EncodeToString(sum[:])
+}
+
+// cached - the token from an earlier run, nil once it is about to expire.
+// A store that cannot be read is treated like an empty one.
+func (h *CredentialHelper) cached(domain string) *HelperToken {
+	key := h.cacheKey(domain)
+	helperTokensMu.Lock()
+	token, ok := helperTokens[key]
+	helperTokensMu.Unlock()
+	if !ok && h.Store != nil {
+		stored, err := h.Store.Get(key)
+		if err != nil || stored == "" {
+			return nil
+		}
+		// base64 since keyringStore.Set rejects quotes, whitespace and
+		// backslashes, which the json always has
+		text, err := base64.RawURLEncoding.DecodeString(stored)
+		if err != nil || json.Unmarshal(text, &token) != nil {
+			return nil
+		}
+	}
+	if token.Token == "" || !h.Now().Add(helperExpiryMargin).Before(token.ExpiresAt) {
+		return nil
+	}
+	return &token
+}
+
+// Get - the token for domain, from the cache while it is valid
+func (h *CredentialHelper) Get(ctx context.Context, domain string) (string, error) {
+	if token := h.cached(domain); token != nil {
+		return token.Token, nil
+	}
+
+	argv, err := h.argv()
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, helperTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdin = strings.NewReader(domain + "\n")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s failed: %s", h.Name(), msg)
+		}
+		return "", fmt.Errorf("%s failed: %w", h.Name(), err)
+	}
+
+	var token HelperToken
+	if err := json.Unmarshal(stdout.Bytes(), &token); err != nil {
+		return "", fmt.Errorf("%s printed invalid JSON: %w", h.Name(), err)
+	}
+	if token.Token == "" {
+		return "", fmt.Errorf("%s printed no token", h.Name())
+	}
+	if token.ExpiresAt.IsZero() {
+		if claims, err := client.ParseClaims(token.Token); err == nil && claims.Expires() {
+			token.ExpiresAt = claims.ExpiresAt
+		}
+	}
+
+	// without an expiry there is no telling how long the token is good for,
+	// so the helper is asked again next time
+	if !token.ExpiresAt.IsZero() {
+		h.save(domain, token)
+	}
+	return token.Token, nil
+}
+
+// Forget - drops the cached token for domain, found reports whether there was one
+func (h *CredentialHelper) Forget(domain string) (bool, error) {
+	key := h.cacheKey(domain)
+	helperTokensMu.Lock()
+	_, found := helperTokens[key]
+	delete(helperTokens, key)
+	helperTokensMu.Unlock()
+	if h.Store == nil {
+		return found, nil
+	}
+	stored, err := h.Store.Delete(key)
+	return found || stored, err
+}
+
+// save - failures are ignored, the cache only saves running the helper
+func (h *CredentialHelper) save(domain string, token HelperToken) {
+	key := h.cacheKey(domain)
+	helperTokensMu.Lock()
+	helperTokens[key] = token
+	helperTokensMu.Unlock()
+	if h.Store == nil {
+		return
+	}
+	text, err := json.Marshal(token)
+	if err != nil {
+		return
+	}
+	_ = h.Store.Set(key, base64.RawURLEncoding.EncodeToString(text))
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// memoryStore - a CredentialStore kept in a map
+type memoryStore map[string]string
+
+func (s memoryStore) Name() string { return "memory" }
+
+func (s memoryStore) Get(domain string) (string, error) { return s[domain], nil }
+
+func (s memoryStore) Set(domain string, token string) error {
+	s[domain] = token
+	return nil
+}
+
+func (s memoryStore) Delete(domain string) (bool, error) {
+	_, ok := s[domain]
+	delete(s, domain)
+	return ok, nil
+}
+
+// writeHelper - a credential helper that counts its runs in a file next to
+// it and prints token-<run>, expiring at expiresAt when that is not empty
+func writeHelper(t *testing.T, expiresAt string) (command string, runs func() int) {
+	dir := t.TempDir()
+	count := path.Join(dir, "runs")
+	expiry := ""
+	if expiresAt != "" {
+		expiry = fmt.Sprintf(`, "expires_at": "%s"`, expiresAt)
+	}
+	script := fmt.Sprintf(`#!/bin/sh
+[ "$1" = get ] || exit 2
+read domain
+echo run >> %s
+n=$(wc -l < %s | tr -d ' ')
+printf '{"token": "token-%%s-%%s"%s}' "$n" "$domain"
+`, count, count, expiry)
+	command = path.Join(dir, "helper")
+	if err := os.WriteFile(command, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+	return command, func() int {
+		text, _ := os.ReadFile(count)
+		return strings.Count(string(text), "run")
+	}
+}
+
+// resetHelperTokens - tokens cached in memory must not leak between tests
+func resetHelperTokens(t *testing.T) {
+	reset := func() {
+		helperTokensMu.Lock()
+		helperTokens = map[string]HelperToken{}
+		helperTokensMu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestCredentialHelperCache(t *testing.T) {
+	const domain = "https://auth.example.com"
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name      string
+		expiresAt string
+		// steps - how much time passes before each Get
+		steps []time.Duration
+		// fresh - every Get after the first is made by a new helper, as in a
+		// new process, which only shares the store
+		fresh    bool
+		useStore bool
+		wantRuns int
+		wantLast string
+	}{
+		{name: "cached within the process", expiresAt: expiresAt, steps: []time.Duration{0, time.Minute, 30 * time.Minute}, wantRuns: 1, wantLast: "token-1-" + domain},
+		{name: "cached across processes in the store", expiresAt: expiresAt, steps: []time.Duration{0, time.Minute}, fresh: true, useStore: true, wantRuns: 1, wantLast: "token-1-" + domain},
+		{name: "not cached across processes without a store", expiresAt: expiresAt, steps: []time.Duration{0, time.Minute}, fresh: true, wantRuns: 2, wantLast: "token-2-" + domain},
+		{name: "run again near the expiry", expiresAt: expiresAt, steps: []time.Duration{0, time.Hour - 30*time.Second}, useStore: true, wantRuns: 2, wantLast: "token-2-" + domain},
+		{name: "run again after the expiry", expiresAt: expiresAt, steps: []time.Duration{0, 2 * time.Hour}, useStore: true, wantRuns: 2, wantLast: "token-2-" + domain},
+		{name: "never cached without an expiry", steps: []time.Duration{0, 0, 0}, useStore: true, wantRuns: 3, wantLast: "token-3-" + domain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetHelperTokens(t)
+			command, runs := writeHelper(t, tt.expiresAt)
+			store := memoryStore{}
+
+			clock := now
+			newHelper := func() *CredentialHelper {
+				var h *CredentialHelper
+				if tt.useStore {
+					h = NewCredentialHelper(command, store)
+				} else {
+					h = NewCredentialHelper(command, nil)
+				}
+				h.Now = func() time.Time { return clock }
+				return h
+			}
+
+			h := newHelper()
+			var token string
+			for i, step := range tt.steps {
+				clock = clock.Add(step)
+				if tt.fresh && i > 0 {
+					// a new process starts with nothing in memory
+					helperTokensMu.Lock()
+					helperTokens = map[string]HelperToken{}
+					helperTokensMu.Unlock()
+					h = newHelper()
+				}
+				var err error
+				token, err = h.Get(context.Background(), domain)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := runs(); got != tt.wantRuns {
+				t.Errorf("helper ran %d times, want %d", got, tt.wantRuns)
+			}
+			if token != tt.wantLast {
+				t.Errorf("token = %q, want %q", token, tt.wantLast)
+			}
+			for key, value := range store {
+				if !strings.HasPrefix(key, "credential-helper:") || strings.Contains(value, "token-") {
+					t.Errorf("stored %q = %q, want a prefixed key and an encoded value", key, value)
+				}
+			}
+		})
+	}
+}
+
+func TestCredentialHelperKeys(t *testing.T) {
+	resetHelperTokens(t)
+	command, runs := writeHelper(t, time.Now().Add(time.Hour).Format(time.RFC3339))
+	store := memoryStore{}
+	h := NewCredentialHelper(command, store)
+
+	// every auth domain gets a token of its own
+	for _, domain := range []string{"https://auth.example.com", "https://auth.staging.example.com", "https://auth.example.com"} {
+		token, err := h.Get(context.Background(), domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(token, domain) {
+			t.Errorf("token %q was handed out for %s", token, domain)
+		}
+	}
+	if got := runs(); got != 2 {
+		t.Errorf("helper ran %d times, want 2", got)
+	}
+
+	// another helper does not share them
+	other := NewCredentialHelper("vault", store)
+	if other.cacheKey("https://auth.example.com") == h.cacheKey("https://auth.example.com") {
+		t.Error("different commands share a cache key")
+	}
+}
+
+func TestCredentialHelperForget(t *testing.T) {
+	const domain = "https://auth.example.com"
+	tests := []struct {
+		name      string
+		useStore  bool
+		get       bool
+		wantFound bool
+	}{
+		{name: "cached in the store", useStore: true, get: true, wantFound: true},
+		{name: "cached in memory", get: true, wantFound: true},
+		{name: "nothing cached", useStore: true, get: false, wantFound: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetHelperTokens(t)
+			command, runs := writeHelper(t, time.Now().Add(time.Hour).Format(time.RFC3339))
+			store := memoryStore{}
+			h := NewCredentialHelper(command, nil)
+			if tt.useStore {
+				h.Store = store
+			}
+
+			if tt.get {
+				if _, err := h.Get(context.Background(), domain); err != nil {
+					t.Fatal(err)
+				}
+			}
+			found, err := h.Forget(domain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != tt.wantFound {
+				t.Errorf("Forget found = %v, want %v", found, tt.wantFound)
+			}
+			if len(store) != 0 {
+				t.Errorf("store still holds %v", store)
+			}
+
+			before := runs()
+			if _, err := h.Get(context.Background(), domain); err != nil {
+				t.Fatal(err)
+			}
+			if runs() != before+1 {
+				t.Error("the helper did not run again after Forget")
+			}
+		})
+	}
+}
+
+func TestCredentialHelperErrors(t *testing.T) {
+	dir := t.TempDir()
+	script := func(name, body string) string {
+		file := path.Join(dir, name)
+		if err := os.WriteFile(file, []byte("#!/bin/sh\n"+body+"\n"), 0700); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+
+	tests := []struct {
+		name    string
+		command string
+		wantErr string
+	}{
+		{name: "empty command", command: " ", wantErr: "credential-helper is empty"},
+		{name: "fails", command: script("fails", "echo vault is sealed >&2; exit 1"), wantErr: "vault is sealed"},
+		{name: "not json", command: script("text", "echo hello"), wantErr: "printed invalid JSON"},
+		{name: "no token", command: script("empty", `echo '{"token": ""}'`), wantErr: "printed no token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetHelperTokens(t)
+			_, err := NewCredentialHelper(tt.command, nil).Get(context.Background(), "https://auth.example.com")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenPassesContextToHelper(t *testing.T) {
+	resetHelperTokens(t)
+	t.Setenv("APTIBLE_ACCESS_TOKEN", "")
+	t.Setenv("HOME", t.TempDir())
+	command, runs := writeHelper(t, "")
+
+	v := viper.New()
+	v.Set("credential-helper", command)
+	v.Set("auth-domain", "auth.example.com")
+
+	token, source, err := Token(context.Background(), v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, "token-1-") || source != "credential-helper "+command {
+		t.Errorf("Token = %q from %q", token, source)
+	}
+
+	// a cancelled run does not wait for the helper
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := Token(ctx, v); err == nil {
+		t.Error("Token succeeded with a cancelled context")
+	}
+	if got := runs(); got != 1 {
+		t.Errorf("helper ran %d times, want 1", got)
+	}
+}
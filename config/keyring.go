@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keyringService - the service name tokens are stored under
+const keyringService = "aptible"
+
+// runKeyring - runs a keyring command with stdin, returns its stdout
+var runKeyring = func(name string, args []string, stdin string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return stdout.String(), &keyringError{name: name, code: exitErr.ExitCode(), stderr: strings.TrimSpace(stderr.String())}
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+type keyringError struct {
+	name   string
+	code   int
+	stderr string
+}
+
+func (e *keyringError) Error() string {
+	if e.stderr != "" {
+		return fmt.Sprintf("%s failed: %s", e.name, e.stderr)
+	}
+	return fmt.Sprintf("%s failed with exit code %d", e.name, e.code)
+}
+
+// keyringAvailable - there are no keyring libraries to link against, so the
+// platform's command line tools are used: secret-tool talks to the Secret
+// Service over the session bus, security to the macOS keychain
+func keyringAvailable() bool {
+	switch runtime.GOOS {
+	case "darwin":
+		_, err := exec.LookPath("security")
+		return err == nil
+	case "linux", "freebsd", "openbsd":
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return false
+		}
+		_, err := exec.LookPath("secret-tool")
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// keyringStore - tokens kept in the OS keyring, one entry per auth domain
+type keyringStore struct {
+	darwin bool
+}
+
+func newKeyringStore() (*keyringStore, error) {
+	if !keyringAvailable() {
+		return nil, fmt.Errorf("no keyring available, install secret-tool (libsecret) and run a Secret Service like gnome-keyring, or set credential-store to %s", CredentialStoreEncryptedFile)
+	}
+	return &keyringStore{darwin: runtime.GOOS == "darwin"}, nil
+}
+
+func (s *keyringStore) Name() string {
+	if s.darwin {
+		return "keyring (macOS keychain)"
+	}
+	return "keyring (Secret Service)"
+}
+
+func (s *keyringStore) Get(domain string) (string, error) {
+	var (
+		out string
+		err error
+	)
+	if s.darwin {
+		out, err = runKeyring("security", []string{"find-generic-password", "-s", keyringService, "-a", domain, "-w"}, "")
+	} else {
+		out, err = runKeyring("secret-tool", []string{"lookup", "service", keyringService, "domain", domain}, "")
+	}
+	var keyErr *keyringError
+	if errors.As(err, &keyErr) && s.missing(keyErr) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func (s *keyringStore) Set(domain string, token string) error {
+	if s.darwin {
+		// add-generic-password only takes the password as an argument, so the
+		// command is fed to `security -i` on stdin to keep the token out of
+		// ps.  -U updates the entry when there already is one.
+		if strings.ContainsAny(token, "\"\\ \t\r\n") || strings.ContainsAny(domain, "\"\\\r\n") {
+			return fmt.Errorf("the token or auth domain contains characters the keychain cannot be sent, set credential-store to %s", CredentialStoreEncryptedFile)
+		}
+		command := fmt.Sprintf("add-generic-password -U -s %s -a \"%s\" -w \"%s\"\n", keyringService, domain, token)
+		if _, err := runKeyring("security", []string{"-i"}, command); err != nil {
+			return err
+		}
+		// security -i reports a failed command on stderr but may still exit
+		// with 0, reading the entry back is the only sure way to know
+		stored, err := s.Get(domain)
+		if err != nil {
+			return err
+		}
+		if stored != token {
+			return fmt.Errorf("security did not store the token in the keychain")
+		}
+		return nil
+	}
+	// secret-tool reads the secret from stdin so it never shows up in ps
+	label := fmt.Sprintf("Aptible token for %s", domain)
+	_, err := runKeyring("secret-tool", []string{"store", "--label", label, "service", keyringService, "domain", domain}, token)
+	return err
+}
+
+func (s *keyringStore) Delete(domain string) (bool, error) {
+	token, err := s.Get(domain)
+	if err != nil || token == "" {
+		return false, err
+	}
+	if s.darwin {
+		_, err = runKeyring("security", []string{"delete-generic-password", "-s", keyringService, "-a", domain}, "")
+	} else {
+		_, err = runKeyring("secret-tool", []string{"clear", "service", keyringService, "domain", domain}, "")
+	}
+	return err == nil, err
+}
+
+// missing - how each tool reports that there is no such entry
+func (s *keyringStore) missing(err *keyringError) bool {
+	if s.darwin {
+		// errSecItemNotFound
+		return err.code == 44
+	}
+	// secret-tool lookup exits with 1 and prints nothing
+	return err.code == 1 && err.stderr == ""
+}
@@ -0,0 +1,255 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// Types a setting's value is written to the file as
+const (
+	TypeString = "string"
+	TypeBool   = "bool"
+	TypeInt    = "int"
+	TypeFloat  = "float"
+)
+
+// Setting - a key the cli reads from the config file
+type Setting struct {
+	Key         string
+	Description string
+	// Type - TypeString when empty
+	Type string
+	// Default - used when nothing sets the key, flags carry their own
+	Default string
+	// Secret - redacted by `config view`
+	Secret   bool
+	Validate func(value string) error
+}
+
+// Parse - validates value and converts it to the yaml type viper expects,
+// e.g. `debug: true` rather than `debug: "true"`
+func (s Setting) Parse(value string) (interface{}, error) {
+	if s.Validate != nil {
+		if err := s.Validate(value); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", s.Key, err)
+		}
+	}
+	switch s.Type {
+	case TypeBool:
+		return strconv.ParseBool(value)
+	case TypeInt:
+		return strconv.Atoi(value)
+	case TypeFloat:
+		return strconv.ParseFloat(value, 64)
+	default:
+		return value, nil
+	}
+}
+
+func validateBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("%q is not true or false", value)
+	}
+	return nil
+}
+
+func validateDuration(value string) error {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a duration such as 30s or 2m", value)
+	}
+	if d < 0 {
+		return fmt.Errorf("%s is negative", value)
+	}
+	return nil
+}
+
+func validateIntAtLeast(min int) func(string) error {
+	return func(value string) error {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		if i < min {
+			return fmt.Errorf("%d is less than %d", i, min)
+		}
+		return nil
+	}
+}
+
+func validateFloatAtLeast(min float64) func(string) error {
+	return func(value string) error {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		if f < min {
+			return fmt.Errorf("%g is less than %g", f, min)
+		}
+		return nil
+	}
+}
+
+func validateOneOf(choices ...string) func(string) error {
+	return func(value string) error {
+		for _, choice := range choices {
+			if value == choice {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(choices, ", "))
+	}
+}
+
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%q is not a url such as http://proxy.example.com:3128", value)
+	}
+	return nil
+}
+
+func validateNotEmpty(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("it cannot be empty")
+	}
+	return nil
+}
+
+func validateCassette(value string) error {
+	_, err := client.ParseCassetteMode(value)
+	return err
+}
+
+// Settings - every key `config set` accepts, in the order `config view`
+// lists them.  contexts and current-context are managed by `aptible context`.
+var Settings = func() []Setting {
+	retry := client.DefaultRetryPolicy()
+	limit := client.DefaultRateLimit()
+	return []Setting{
+		{Key: "api-domain", Description: "api domain, or a full url", Validate: validateNotEmpty},
+		{Key: "api-scheme", Description: "scheme used to reach api-domain", Validate: validateOneOf("https", "http")},
+		{Key: "auth-domain", Description: "auth domain", Validate: validateNotEmpty},
+		{Key: "org", Description: "organization id or name"},
+		{Key: "env", Description: "environment id or name"},
+		{Key: "default-org", Description: "organization forms start on, see `aptible org use`"},
+		{Key: "default-env", Description: "environment forms start on, see `aptible env use`"},
+		{Key: "token", Description: "token, prefer `aptible login`", Secret: true},
+		{Key: "timeout", Description: "abort commands that take longer, 0 waits forever", Validate: validateDuration},
+		{Key: "token-expiry-warning", Description: "warn when the token expires sooner", Default: "10m", Validate: validateDuration},
+		{Key: "credential-store", Description: "where login keeps tokens", Default: CredentialStoreAuto, Validate: validateOneOf(CredentialStoreAuto, CredentialStoreKeyring, CredentialStoreEncryptedFile, CredentialStoreFile)},
+		{Key: "credential-helper", Description: "executable that prints a token, see the README"},
+		{Key: "debug", Description: "debug logging", Type: TypeBool, Validate: validateBool},
+		{Key: "debug-file", Description: "write debug output to this file"},
+		{Key: "debug-har", Description: "record api traffic to this HAR file"},
+		{Key: "no-cache", Description: "always fetch instead of using cached lookups", Type: TypeBool, Validate: validateBool},
+		{Key: "cassette", Description: "record or replay api traffic", Validate: validateCassette},
+		{Key: "cassette-file", Description: "file cassettes are kept in", Default: "aptible-cassette.yaml"},
+		{Key: "retry.max-attempts", Description: "attempts per request, 1 disables retries", Default: strconv.Itoa(retry.MaxAttempts), Type: TypeInt, Validate: validateIntAtLeast(1)},
+		{Key: "retry.base-delay", Description: "first backoff delay", Default: retry.BaseDelay.String(), Validate: validateDuration},
+		{Key: "retry.max-delay", Description: "longest backoff delay", Default: retry.MaxDelay.String(), Validate: validateDuration},
+		{Key: "retry.non-idempotent", Description: "also retry POST and PATCH", Default: strconv.FormatBool(retry.RetryNonIdempotent), Type: TypeBool, Validate: validateBool},
+		{Key: "rate-limit.requests-per-second", Description: "client side rate limit, 0 disables it", Default: strconv.FormatFloat(limit.RequestsPerSecond, 'g', -1, 64), Type: TypeFloat, Validate: validateFloatAtLeast(0)},
+		{Key: "rate-limit.burst", Description: "requests allowed at once", Default: strconv.Itoa(limit.Burst), Type: TypeInt, Validate: validateIntAtLeast(1)},
+		{Key: "proxy", Description: "proxy url, defaults to HTTPS_PROXY", Validate: validateURL},
+		{Key: "ca-cert", Description: "PEM bundle trusted on top of the system roots"},
+		{Key: "client-cert", Description: "PEM client certificate"},
+		{Key: "client-key", Description: "PEM key of client-cert"},
+		{Key: "insecure-skip-verify", Description: "skip TLS verification", Default: "false", Type: TypeBool, Validate: validateBool},
+	}
+}()
+
+// FindSetting - the setting for key, false when the cli does not know it
+func FindSetting(key string) (Setting, bool) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// SetDefaults - registers the defaults of the settings without a flag
+func SetDefaults(v *viper.Viper) {
+	for _, s := range Settings {
+		if s.Default != "" {
+			v.SetDefault(s.Key, s.Default)
+		}
+	}
+}
+
+// Sources a value can come from, highest precedence first
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceContext = "context"
+	SourceFile    = "file"
+	SourceDefault = "default"
+)
+
+// Value - an effective setting and where it came from
+type Value struct {
+	Key    string
+	Value  string
+	Source string
+	// Origin - the flag, environment variable, context or file
+	Origin string
+}
+
+// EnvVar - the environment variable viper reads key from
+func EnvVar(key string) string {
+	return "APTIBLE_" + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+}
+
+// Lookup - the value commands see for key.  Viper only hands out the
+// winner so the sources are checked in viper's order: flags, environment
+// variables, the config file (the active context's keys first) and
+// defaults.  The value is empty and Source too when nothing sets key.
+func Lookup(v *viper.Viper, cmd *cobra.Command, f *File, key string) Value {
+	value := Value{Key: key, Value: fmt.Sprint(v.Get(key))}
+	if v.Get(key) == nil {
+		value.Value = ""
+	}
+
+	var flagDefault *string
+	if flag := cmd.Flags().Lookup(key); flag != nil {
+		if flag.Changed {
+			value.Source, value.Origin = SourceFlag, "--"+key
+			return value
+		}
+		flagDefault = &flag.DefValue
+	}
+	// viper ignores empty environment variables
+	if os.Getenv(EnvVar(key)) != "" {
+		value.Source, value.Origin = SourceEnv, EnvVar(key)
+		return value
+	}
+	keys := strings.Split(key, ".")
+	if name := ActiveContext(); name != "" {
+		if _, ok := f.Get(append([]string{"contexts", name}, keys...)...); ok {
+			value.Source, value.Origin = SourceContext, name
+			return value
+		}
+	}
+	if _, ok := f.Get(keys...); ok {
+		value.Source, value.Origin = SourceFile, f.Path
+		return value
+	}
+
+	if flagDefault != nil {
+		value.Value = *flagDefault
+	}
+	if value.Value != "" {
+		value.Source = SourceDefault
+	}
+	return value
+}
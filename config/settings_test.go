@@ -0,0 +1,125 @@
+package config
+
+import (
+	"io"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// newViper - set up like cmd's initConfig, reading file and applying its context
+func newViper(t *testing.T, file string, cmd *cobra.Command) *viper.Viper {
+	t.Cleanup(func() { activeContext = "" })
+
+	v := viper.New()
+	v.SetConfigFile(file)
+	SetDefaults(v)
+	v.AutomaticEnv()
+	v.SetEnvPrefix("APTIBLE")
+	v.SetEnvKeyReplacer(strings.NewReplacer("-", "_", ".", "_"))
+	if err := v.BindPFlags(cmd.Flags()); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyContext(v, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestLookup(t *testing.T) {
+	const contents = `org: acme
+api-domain: api.example.com
+retry:
+  max-attempts: 5
+contexts:
+  staging:
+    api-domain: api.staging.example.com
+`
+
+	tests := []struct {
+		name       string
+		key        string
+		env        map[string]string
+		flags      []string
+		wantValue  string
+		wantSource string
+		wantOrigin string
+	}{
+		{name: "flag", key: "org", flags: []string{"--org", "initech"}, wantValue: "initech", wantSource: SourceFlag, wantOrigin: "--org"},
+		{name: "flag beats env", key: "org", flags: []string{"--org", "initech"}, env: map[string]string{"APTIBLE_ORG": "globex"}, wantValue: "initech", wantSource: SourceFlag},
+		{name: "env", key: "org", env: map[string]string{"APTIBLE_ORG": "globex"}, wantValue: "globex", wantSource: SourceEnv, wantOrigin: "APTIBLE_ORG"},
+		{name: "nested env", key: "retry.max-attempts", env: map[string]string{"APTIBLE_RETRY_MAX_ATTEMPTS": "2"}, wantValue: "2", wantSource: SourceEnv, wantOrigin: "APTIBLE_RETRY_MAX_ATTEMPTS"},
+		{name: "empty env is ignored", key: "org", env: map[string]string{"APTIBLE_ORG": ""}, wantValue: "acme", wantSource: SourceFile},
+		{
+			name: "context", key: "api-domain", env: map[string]string{"APTIBLE_CONTEXT": "staging"},
+			wantValue: "api.staging.example.com", wantSource: SourceContext, wantOrigin: "staging",
+		},
+		{name: "file below the context", key: "org", env: map[string]string{"APTIBLE_CONTEXT": "staging"}, wantValue: "acme", wantSource: SourceFile},
+		{name: "file", key: "api-domain", wantValue: "api.example.com", wantSource: SourceFile},
+		{name: "nested file", key: "retry.max-attempts", wantValue: "5", wantSource: SourceFile},
+		{name: "setting default", key: "token-expiry-warning", wantValue: "10m", wantSource: SourceDefault},
+		{name: "flag default", key: "api-scheme", wantValue: "https", wantSource: SourceDefault},
+		{name: "unset", key: "env", wantValue: "", wantSource: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the environment the test runs in must not leak into the lookup
+			for _, key := range []string{"org", "api-domain", "retry.max-attempts", "token-expiry-warning", "api-scheme", "env", "context"} {
+				t.Setenv(EnvVar(key), "")
+				os.Unsetenv(EnvVar(key))
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			file := path.Join(t.TempDir(), ".aptible.yaml")
+			if err := os.WriteFile(file, []byte(contents), 0600); err != nil {
+				t.Fatal(err)
+			}
+			f, err := LoadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cmd := &cobra.Command{Use: "test"}
+			cmd.Flags().String("org", "", "")
+			cmd.Flags().String("api-scheme", "https", "")
+			if err := cmd.Flags().Parse(tt.flags); err != nil {
+				t.Fatal(err)
+			}
+			v := newViper(t, file, cmd)
+
+			got := Lookup(v, cmd, f, tt.key)
+			if got.Value != tt.wantValue || got.Source != tt.wantSource {
+				t.Errorf("Lookup(%s) = %q from %q, want %q from %q", tt.key, got.Value, got.Source, tt.wantValue, tt.wantSource)
+			}
+			if tt.wantOrigin != "" && got.Origin != tt.wantOrigin {
+				t.Errorf("origin = %q, want %q", got.Origin, tt.wantOrigin)
+			}
+			if tt.wantSource == SourceFile && got.Origin != file {
+				t.Errorf("origin = %q, want %s", got.Origin, file)
+			}
+		})
+	}
+}
+
+func TestEnvVar(t *testing.T) {
+	tests := map[string]string{
+		"org":                            "APTIBLE_ORG",
+		"api-domain":                     "APTIBLE_API_DOMAIN",
+		"retry.max-attempts":             "APTIBLE_RETRY_MAX_ATTEMPTS",
+		"rate-limit.requests-per-second": "APTIBLE_RATE_LIMIT_REQUESTS_PER_SECOND",
+	}
+	for key, want := range tests {
+		if got := EnvVar(key); got != want {
+			t.Errorf("EnvVar(%s) = %s, want %s", key, got, want)
+		}
+	}
+}
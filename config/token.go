@@ -0,0 +1,93 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// Token - the token commands use and where it came from.  --token and
+// APTIBLE_TOKEN come first, then APTIBLE_ACCESS_TOKEN, the
+// `credential-helper` and last the credential store.  The token is empty
+// when there is none.  ctx bounds the credential-helper.
+func Token(ctx context.Context, v *viper.Viper) (string, string, error) {
+	if token := v.GetString("token"); token != "" {
+		return token, tokenSource(v, token), nil
+	}
+	// set by CI systems and wrappers that fetch a token for a single run
+	if token := os.Getenv("APTIBLE_ACCESS_TOKEN"); token != "" {
+		return token, "APTIBLE_ACCESS_TOKEN", nil
+	}
+
+	if command := v.GetString("credential-helper"); command != "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", err
+		}
+		helper := NewCredentialHelper(command, HelperCacheStore(v, home))
+		token, err := helper.Get(ctx, AuthURL(v))
+		if err != nil {
+			return "", "", err
+		}
+		return token, helper.Name(), nil
+	}
+
+	store, err := OpenCredentialStore(v)
+	if err != nil {
+		return "", "", err
+	}
+	token, err := store.Get(AuthURL(v))
+	if err != nil {
+		return "", "", err
+	}
+	return token, store.Name(), nil
+}
+
+// tokenSource - viper does not say where a value came from, flags win over
+// the environment which wins over the config file
+func tokenSource(v *viper.Viper, token string) string {
+	if os.Getenv("APTIBLE_TOKEN") == token {
+		return "APTIBLE_TOKEN"
+	}
+	if v.InConfig("token") && v.ConfigFileUsed() != "" {
+		return v.ConfigFileUsed()
+	}
+	return "--token"
+}
+
+// CheckTokenExpiry - warns on w when the token expires within the
+// `token-expiry-warning` setting and returns an unauthorized error once it
+// has expired, tokens that are not JWTs are left for the api to judge
+func CheckTokenExpiry(w io.Writer, v *viper.Viper, token string, now time.Time) error {
+	claims, err := client.ParseClaims(token)
+	if err != nil {
+		return nil
+	}
+
+	if claims.Expired(now) {
+		return &client.APIError{
+			Kind:    client.KindUnauthorized,
+			Message: fmt.Sprintf("the token expired %s ago, run `aptible login` to get a new one", FormatDuration(-claims.ExpiresIn(now))),
+		}
+	}
+	warning := v.GetDuration("token-expiry-warning")
+	if claims.Expires() && warning > 0 && claims.ExpiresIn(now) <= warning {
+		fmt.Fprintf(w, "Warning: the token expires in %s, run `aptible login` to renew it.\n", FormatDuration(claims.ExpiresIn(now)))
+	}
+	return nil
+}
+
+// FormatDuration - rounded to what matters for a token, e.g. 9m30s or 11h59m
+func FormatDuration(d time.Duration) string {
+	if d >= time.Hour {
+		return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+	}
+	return d.Round(time.Second).String()
+}
@@ -2,9 +2,11 @@ package libasset
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/aptible/cloud-cli/config"
 	"github.com/aptible/cloud-cli/lib/env"
+	libresolve "github.com/aptible/cloud-cli/lib/resolve"
 	"github.com/aptible/cloud-cli/ui/form"
 	"github.com/charmbracelet/bubbles/list"
 )
@@ -19,7 +21,7 @@ func NewAssetNameProp() *form.SubSchema {
 func CreateAssetTypeOptions(orgId, envId string) form.LoadOptionsFn {
 	options := []list.Item{}
 	return func(cfg *config.CloudConfig) ([]list.Item, error) {
-		bundles, err := cfg.Cc.ListAssetBundles(orgId, envId)
+		bundles, err := cfg.Cc.ListAssetBundles(cfg.Ctx, orgId, envId)
 		if err != nil {
 			return options, err
 		}
@@ -41,7 +43,7 @@ func NewAssetTypeProp(orgId, envId string) *form.SubSchema {
 func CreateVPCOptions(orgId, envId string) form.LoadOptionsFn {
 	options := []list.Item{}
 	return func(cfg *config.CloudConfig) ([]list.Item, error) {
-		assets, err := cfg.Cc.ListAssets(orgId, envId)
+		assets, err := cfg.Cc.ListAssets(cfg.Ctx, orgId, envId)
 		if err != nil {
 			return options, err
 		}
@@ -194,13 +196,18 @@ func AssetEngineVersionForm(cfg *config.CloudConfig, results *form.FormResult) e
 	return nil
 }
 
-func CreateAssetOptions(orgId, envId string) form.LoadOptionsFn {
+// CreateAssetOptions - the assets of the environment, only those of types
+// when any are given
+func CreateAssetOptions(orgId, envId string, types ...string) form.LoadOptionsFn {
 	options := []list.Item{}
 	return func(cfg *config.CloudConfig) ([]list.Item, error) {
-		assets, err := cfg.Cc.ListAssets(orgId, envId)
+		assets, err := cfg.Cc.ListAssets(cfg.Ctx, orgId, envId)
 		if err != nil {
 			return options, err
 		}
+		if len(types) > 0 {
+			assets = FilterByType(assets, types)
+		}
 		for _, asset := range assets {
 			name := GetName(asset)
 			options = append(options, form.FormOption{Label: name, Value: asset.Id})
@@ -209,17 +216,74 @@ func CreateAssetOptions(orgId, envId string) form.LoadOptionsFn {
 	}
 }
 
-func NewAssetProp(orgId, envId string) *form.SubSchema {
+func NewAssetProp(orgId, envId string, types ...string) *form.SubSchema {
 	return &form.SubSchema{
 		Type:        "select",
 		Title:       "Select an asset",
-		LoadOptions: CreateAssetOptions(orgId, envId),
+		LoadOptions: CreateAssetOptions(orgId, envId, types...),
+	}
+}
+
+// ResolveAsset - the id of the asset ref names in the environment, ref may
+// be an id or the name shown by GetName
+func ResolveAsset(cfg *config.CloudConfig, orgId, envId string, ref string) (string, error) {
+	if libresolve.IsId(ref) {
+		return ref, nil
+	}
+	assets, err := cfg.Cc.ListAssets(cfg.Ctx, orgId, envId)
+	if err != nil {
+		return "", err
+	}
+	candidates := make([]libresolve.Candidate, 0, len(assets))
+	for _, asset := range assets {
+		candidates = append(candidates, libresolve.Candidate{Id: asset.Id, Name: GetName(asset)})
+	}
+	asset, err := libresolve.One("asset", ref, candidates)
+	return asset.Id, err
+}
+
+// ResolveAssetExact - like ResolveAsset for commands that destroy the asset:
+// only assets of types match, every type when there are none, names have to
+// match exactly and ids are looked up too so one of another type is refused.
+// kind names the asset in errors, e.g. "network".
+func ResolveAssetExact(cfg *config.CloudConfig, orgId, envId string, kind string, types []string, ref string) (string, error) {
+	assets, err := cfg.Cc.ListAssets(cfg.Ctx, orgId, envId)
+	if err != nil {
+		return "", err
 	}
+	if len(types) > 0 {
+		assets = FilterByType(assets, types)
+	}
+	candidates := make([]libresolve.Candidate, 0, len(assets))
+	for _, asset := range assets {
+		candidates = append(candidates, libresolve.Candidate{Id: asset.Id, Name: GetName(asset)})
+	}
+	asset, err := libresolve.Exact(kind, ref, candidates)
+	return asset.Id, err
+}
+
+// ResolveAssetRef - like ResolveAsset, the environment is only asked for
+// when ref is a name since ids are unique across environments
+func ResolveAssetRef(cfg *config.CloudConfig, results *form.FormResult, ref string) (string, error) {
+	if ref == "" || libresolve.IsId(ref) {
+		return ref, nil
+	}
+	if err := libenv.EnvForm(cfg, results); err != nil {
+		return "", err
+	}
+	return ResolveAsset(cfg, results.Org, results.Env, ref)
+}
+
+// ResolveAssetForm - resolves an optional --asset, never prompts for one
+func ResolveAssetForm(cfg *config.CloudConfig, results *form.FormResult) error {
+	asset, err := ResolveAssetRef(cfg, results, results.Asset)
+	results.Asset = asset
+	return err
 }
 
 func AssetForm(cfg *config.CloudConfig, results *form.FormResult) error {
 	if results.Asset != "" {
-		return nil
+		return ResolveAssetForm(cfg, results)
 	}
 
 	prop := NewAssetProp(results.Org, results.Env)
@@ -235,6 +299,36 @@ func AssetForm(cfg *config.CloudConfig, results *form.FormResult) error {
 	return nil
 }
 
+// AssetParamsForm - prompts for every scalar parameter of an asset with its
+// current value filled in, only the values that were changed are returned
+func AssetParamsForm(cfg *config.CloudConfig, current map[string]interface{}) (map[string]string, error) {
+	keys := make([]string, 0, len(current))
+	for key, value := range current {
+		if IsScalarParam(value) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changed := map[string]string{}
+	for _, key := range keys {
+		prop := &form.SubSchema{
+			Type:  "input",
+			Title: key,
+			Value: FormatParam(current[key]),
+		}
+		result, err := form.Run(form.NewModel(cfg, prop))
+		if err != nil {
+			return nil, err
+		}
+		if result != prop.Value {
+			changed[key] = result
+		}
+	}
+
+	return changed, nil
+}
+
 func AssetDescribeForm(cfg *config.CloudConfig, results *form.FormResult) error {
 	forms := []form.FormFn{
 		libenv.EnvForm,
@@ -251,6 +345,32 @@ func AssetDescribeForm(cfg *config.CloudConfig, results *form.FormResult) error
 	return nil
 }
 
+// AssetDestroyForm - AssetDescribeForm for commands that destroy the asset,
+// see ResolveAssetExact.  The prompt only offers assets of types.
+func AssetDestroyForm(kind string, types []string) form.FormFn {
+	return func(cfg *config.CloudConfig, results *form.FormResult) error {
+		if err := libenv.EnvForm(cfg, results); err != nil {
+			return err
+		}
+		if results.Asset != "" {
+			asset, err := ResolveAssetExact(cfg, results.Org, results.Env, kind, types, results.Asset)
+			results.Asset = asset
+			return err
+		}
+
+		prop := NewAssetProp(results.Org, results.Env, types...)
+		result, err := form.Run(form.NewModel(cfg, prop))
+		if err != nil {
+			return err
+		}
+		if result == "" {
+			return fmt.Errorf("You must select an asset")
+		}
+		results.Asset = result
+		return nil
+	}
+}
+
 func AssetCreateForm(cfg *config.CloudConfig, results *form.FormResult) error {
 	forms := []form.FormFn{
 		libenv.EnvForm,
@@ -0,0 +1,73 @@
+package libasset
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// EnvAssets - the assets of a single environment, Err is set when they could not be listed
+type EnvAssets struct {
+	Env    cac.EnvironmentOutput
+	Assets []cac.AssetOutput
+	Err    error
+}
+
+// Inventory - lists the assets of every environment concurrently with at most
+// `workers` requests in flight, results keep the order of envs
+func Inventory(ctx context.Context, cc client.CloudClient, orgId string, envs []cac.EnvironmentOutput, workers int) []EnvAssets {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]EnvAssets, len(envs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				assets, err := cc.ListAssets(ctx, orgId, envs[i].Id)
+				results[i] = EnvAssets{Env: envs[i], Assets: assets, Err: err}
+			}
+		}()
+	}
+
+	for i := range envs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// PartialError - some, but not necessarily all, environments failed to list
+type PartialError struct {
+	Failed []EnvAssets
+	Total  int
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("unable to list assets for %d of %d environment(s)", len(e.Failed), e.Total)
+}
+
+// InventoryErr - nil when every environment was listed
+func InventoryErr(results []EnvAssets) error {
+	failed := []EnvAssets{}
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	return &PartialError{Failed: failed, Total: len(results)}
+}
@@ -0,0 +1,101 @@
+package libasset
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// FormatParam - renders a parameter value the way it is entered on the command line
+func FormatParam(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// IsScalarParam - whether the value can be edited as a single line of text
+func IsScalarParam(value interface{}) bool {
+	switch value.(type) {
+	case nil, string, bool, float64, float32, int, int64:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseParam - converts raw to the type of the value it replaces so numbers
+// and booleans are not sent back to the api as strings.  Maps and lists have
+// no single line form, so they are rejected rather than replaced by a string.
+func parseParam(key, raw string, current interface{}) (interface{}, error) {
+	switch current.(type) {
+	case string:
+		return raw, nil
+	case nil:
+		// a new parameter, or one that is null, has no type to keep
+		return raw, nil
+	case bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s must be true or false, got %q", key, raw)
+		}
+		return value, nil
+	case float64, float32, int, int64:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s must be a number, got %q", key, raw)
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("parameter %s is structured and cannot be set from the command line", key)
+	}
+}
+
+// ApplyParams - copy of current with the overrides applied
+func ApplyParams(current map[string]interface{}, overrides map[string]string) (map[string]interface{}, error) {
+	updated := map[string]interface{}{}
+	for key, value := range current {
+		updated[key] = value
+	}
+	for key, raw := range overrides {
+		value, err := parseParam(key, raw, current[key])
+		if err != nil {
+			return nil, err
+		}
+		updated[key] = value
+	}
+	return updated, nil
+}
+
+// ParamDiff - one line per added (+), removed (-) or changed (~) parameter,
+// sorted by key, empty when nothing changed
+func ParamDiff(before, after map[string]interface{}) []string {
+	keys := map[string]bool{}
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	lines := []string{}
+	for _, key := range sorted {
+		old, hadOld := before[key]
+		cur, hasCur := after[key]
+		switch {
+		case !hadOld:
+			lines = append(lines, fmt.Sprintf("+ %s: %s", key, FormatParam(cur)))
+		case !hasCur:
+			lines = append(lines, fmt.Sprintf("- %s: %s", key, FormatParam(old)))
+		case !reflect.DeepEqual(old, cur):
+			lines = append(lines, fmt.Sprintf("~ %s: %s => %s", key, FormatParam(old), FormatParam(cur)))
+		}
+	}
+	return lines
+}
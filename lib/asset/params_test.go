@@ -0,0 +1,84 @@
+package libasset
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseParam(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		current interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{name: "string", raw: "db-2", current: "db-1", want: "db-2"},
+		{name: "string that looks like a number", raw: "10", current: "5", want: "10"},
+		{name: "nil", raw: "10", current: nil, want: "10"},
+		{name: "bool", raw: "true", current: false, want: true},
+		{name: "not a bool", raw: "yes please", current: false, wantErr: "must be true or false"},
+		{name: "float64", raw: "20", current: float64(10), want: float64(20)},
+		{name: "float32", raw: "2.5", current: float32(1), want: 2.5},
+		{name: "int", raw: "3", current: 1, want: float64(3)},
+		{name: "int64", raw: "4", current: int64(1), want: float64(4)},
+		{name: "not a number", raw: "big", current: float64(10), wantErr: "must be a number"},
+		{name: "map", raw: "x", current: map[string]interface{}{"a": "b"}, wantErr: "is structured"},
+		{name: "slice", raw: "x", current: []interface{}{"a"}, wantErr: "is structured"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseParam("size", tt.raw, tt.current)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseParam(%q) = %#v, want %#v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyParams(t *testing.T) {
+	current := map[string]interface{}{
+		"name":    "db",
+		"size":    float64(10),
+		"tags":    []interface{}{"prod"},
+		"network": map[string]interface{}{"vpc": "demo-vpc"},
+	}
+
+	updated, err := ApplyParams(current, map[string]string{"size": "20", "engine": "postgres"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"name":    "db",
+		"size":    float64(20),
+		"engine":  "postgres",
+		"tags":    []interface{}{"prod"},
+		"network": map[string]interface{}{"vpc": "demo-vpc"},
+	}
+	if !reflect.DeepEqual(updated, want) {
+		t.Errorf("ApplyParams = %v, want %v", updated, want)
+	}
+	if current["size"] != float64(10) {
+		t.Error("ApplyParams changed the current parameters")
+	}
+
+	if _, err := ApplyParams(current, map[string]string{"tags": "prod,staging"}); err == nil {
+		t.Error("overriding a list succeeded")
+	}
+
+	diff := ParamDiff(current, updated)
+	wantDiff := []string{"+ engine: postgres", "~ size: 10 => 20"}
+	if !reflect.DeepEqual(diff, wantDiff) {
+		t.Errorf("ParamDiff = %v, want %v", diff, wantDiff)
+	}
+}
@@ -41,6 +41,17 @@ func generateRowFromData(asset cac.AssetOutput) table.Row {
 	return colorizeFromStatus(asset, row)
 }
 
+func assetColumns() []table.Column {
+	return []table.Column{
+		table.NewColumn("id", "Id", 40).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("status", "Status", 40).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("name", "Name", 20).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("cloud", "Cloud", 20).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("asset_type", "Type", 20).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("asset_version", "Version", 20).WithStyle(common.DefaultRowStyle()),
+	}
+}
+
 func AssetTable(output interface{}) table.Model {
 	rows := make([]table.Row, 0)
 
@@ -53,14 +64,24 @@ func AssetTable(output interface{}) table.Model {
 		rows = append(rows, generateRowFromData(*data))
 	}
 
-	return table.New([]table.Column{
-		table.NewColumn("id", "Id", 40).WithStyle(common.DefaultRowStyle()),
-		table.NewColumn("status", "Status", 40).WithStyle(common.DefaultRowStyle()),
-		table.NewColumn("name", "Name", 20).WithStyle(common.DefaultRowStyle()),
-		table.NewColumn("cloud", "Cloud", 20).WithStyle(common.DefaultRowStyle()),
-		table.NewColumn("asset_type", "Type", 20).WithStyle(common.DefaultRowStyle()),
-		table.NewColumn("asset_version", "Version", 20).WithStyle(common.DefaultRowStyle()),
-	}).WithRows(rows)
+	return table.New(assetColumns()).WithRows(rows)
+}
+
+// InventoryTable - assets across environments, with an Environment column
+func InventoryTable(results []EnvAssets) table.Model {
+	rows := make([]table.Row, 0)
+	for _, result := range results {
+		for _, asset := range result.Assets {
+			row := generateRowFromData(asset)
+			row.Data["environment"] = result.Env.Name
+			rows = append(rows, row)
+		}
+	}
+
+	columns := append([]table.Column{
+		table.NewColumn("environment", "Environment", 20).WithStyle(common.DefaultRowStyle()),
+	}, assetColumns()...)
+	return table.New(columns).WithRows(rows)
 }
 
 func generateRowFromBundleData(bundle cac.AssetBundle) table.Row {
@@ -19,12 +19,25 @@ func GetName(asset cac.AssetOutput) string {
 	}
 }
 
+// GetType - the type segment of an asset, e.g. rds in aws__rds__latest,
+// empty when the asset is not shaped like that
+func GetType(asset cac.AssetOutput) string {
+	segments := strings.Split(asset.Asset, "__")
+	if len(segments) < 2 {
+		return ""
+	}
+	return segments[1]
+}
+
+// FilterByType - the assets whose type is one of types, compared exactly so
+// rds does not match an rds_replica
 func FilterByType(assets []cac.AssetOutput, types []string) []cac.AssetOutput {
 	filteredResults := make([]cac.AssetOutput, 0)
 	for _, result := range assets {
 		for _, _type := range types {
-			if strings.Contains(result.Asset, _type) {
+			if GetType(result) == _type {
 				filteredResults = append(filteredResults, result)
+				break
 			}
 		}
 	}
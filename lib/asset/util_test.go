@@ -0,0 +1,40 @@
+package libasset
+
+import (
+	"reflect"
+	"testing"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+)
+
+func TestFilterByType(t *testing.T) {
+	assets := []cac.AssetOutput{
+		{Id: "db", Asset: "aws__rds__latest"},
+		{Id: "replica", Asset: "aws__rds_replica__latest"},
+		{Id: "network", Asset: "aws__vpc__latest"},
+		{Id: "odd", Asset: "rds"},
+	}
+	tests := []struct {
+		name  string
+		types []string
+		want  []string
+	}{
+		{name: "exact type", types: []string{"rds"}, want: []string{"db"}},
+		{name: "longer type", types: []string{"rds_replica"}, want: []string{"replica"}},
+		{name: "several types", types: []string{"rds", "vpc"}, want: []string{"db", "network"}},
+		// an asset listed under two types is still only returned once
+		{name: "repeated type", types: []string{"vpc", "vpc"}, want: []string{"network"}},
+		{name: "no match", types: []string{"s3"}, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, asset := range FilterByType(assets, tt.types) {
+				got = append(got, asset.Id)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterByType(%v) = %v, want %v", tt.types, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package libauth
+
+import (
+	"fmt"
+
+	"github.com/aptible/cloud-cli/ui/form"
+)
+
+func NewEmailProp() *form.SubSchema {
+	return &form.SubSchema{
+		Type:  "input",
+		Title: "Email",
+	}
+}
+
+func NewPasswordProp() *form.SubSchema {
+	return &form.SubSchema{
+		Type:   "input",
+		Title:  "Password",
+		Secret: true,
+	}
+}
+
+func NewOTPProp() *form.SubSchema {
+	return &form.SubSchema{
+		Type:  "input",
+		Title: "One-time password",
+	}
+}
+
+func NewPassphraseProp(title string) *form.SubSchema {
+	return &form.SubSchema{
+		Type:   "input",
+		Title:  title,
+		Secret: true,
+	}
+}
+
+// PromptPassphrase - asks for the passphrase of the encrypted credential
+// file, twice when it is about to be created, see config.PromptPassphrase
+func PromptPassphrase(confirm bool) (string, error) {
+	passphrase, err := Prompt(NewPassphraseProp("Passphrase for the credential file"))
+	if err != nil || !confirm {
+		return passphrase, err
+	}
+	again, err := Prompt(NewPassphraseProp("Repeat the passphrase"))
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", fmt.Errorf("the passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// Prompt - asks for a single value, no api lookups are involved so it works
+// before the user has a token
+func Prompt(prop *form.SubSchema) (string, error) {
+	value, err := form.Run(form.NewModel(nil, prop))
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", fmt.Errorf("%s is required", prop.Title)
+	}
+	return value, nil
+}
@@ -0,0 +1,40 @@
+package libconfig
+
+import (
+	"fmt"
+
+	"github.com/aptible/cloud-cli/config"
+	"github.com/aptible/cloud-cli/ui/common"
+	"github.com/evertras/bubble-table/table"
+)
+
+// FormatSource - e.g. `env APTIBLE_ORG` or `context staging`
+func FormatSource(value config.Value) string {
+	switch value.Source {
+	case "":
+		return "unset"
+	case config.SourceDefault:
+		return value.Source
+	default:
+		return fmt.Sprintf("%s %s", value.Source, value.Origin)
+	}
+}
+
+// prints out a table of settings and where their values came from
+func SettingTable(values []config.Value) table.Model {
+	rows := make([]table.Row, 0)
+
+	for _, value := range values {
+		rows = append(rows, table.NewRow(table.RowData{
+			"key":    value.Key,
+			"value":  value.Value,
+			"source": FormatSource(value),
+		}))
+	}
+
+	return table.New([]table.Column{
+		table.NewColumn("key", "Key", 32).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("value", "Value", 40).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("source", "Source", 40).WithStyle(common.DefaultRowStyle()),
+	}).WithRows(rows)
+}
@@ -0,0 +1,37 @@
+package libconn
+
+import (
+	"context"
+	"fmt"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// FindConnection - looks a connection up by id among the connections of an
+// asset, or of the whole environment when assetId is empty, so callers that
+// only know the connection id can find the asset it belongs to
+func FindConnection(ctx context.Context, cc client.CloudClient, orgId, envId, assetId, connId string) (*cac.ConnectionOutput, error) {
+	conns, err := cc.ListConnections(ctx, orgId, envId, assetId)
+	if err != nil {
+		return nil, err
+	}
+	for _, conn := range conns {
+		if conn.Id == connId {
+			return &conn, nil
+		}
+	}
+	return nil, &client.APIError{
+		Kind:    client.KindNotFound,
+		Message: fmt.Sprintf("connection %s does not exist in environment %s", connId, envId),
+	}
+}
+
+// AssetId - the asset a connection belongs to, the incoming side
+func AssetId(conn cac.ConnectionOutput) string {
+	if conn.HasIncomingConnectionAsset() {
+		return conn.IncomingConnectionAsset.Id
+	}
+	return ""
+}
@@ -7,11 +7,14 @@ import (
 	libasset "github.com/aptible/cloud-cli/lib/asset"
 	libenv "github.com/aptible/cloud-cli/lib/env"
 	"github.com/aptible/cloud-cli/ui/form"
+	"github.com/charmbracelet/bubbles/list"
 )
 
 func OutAssetForm(cfg *config.CloudConfig, results *form.FormResult) error {
 	if results.OutAsset != "" {
-		return nil
+		asset, err := libasset.ResolveAssetRef(cfg, results, results.OutAsset)
+		results.OutAsset = asset
+		return err
 	}
 
 	prop := libasset.NewAssetProp(results.Org, results.Env)
@@ -30,7 +33,9 @@ func OutAssetForm(cfg *config.CloudConfig, results *form.FormResult) error {
 
 func InAssetForm(cfg *config.CloudConfig, results *form.FormResult) error {
 	if results.InAsset != "" {
-		return nil
+		asset, err := libasset.ResolveAssetRef(cfg, results, results.InAsset)
+		results.InAsset = asset
+		return err
 	}
 
 	prop := libasset.NewAssetProp(results.Org, results.Env)
@@ -89,3 +94,60 @@ func ConnCreateForm(cfg *config.CloudConfig, results *form.FormResult) error {
 
 	return nil
 }
+
+func CreateConnOptions(orgId, envId, assetId string) form.LoadOptionsFn {
+	options := []list.Item{}
+	return func(cfg *config.CloudConfig) ([]list.Item, error) {
+		conns, err := cfg.Cc.ListConnections(cfg.Ctx, orgId, envId, assetId)
+		if err != nil {
+			return options, err
+		}
+		for _, conn := range conns {
+			options = append(options, form.FormOption{Label: GetName(conn), Value: conn.Id})
+		}
+		return options, nil
+	}
+}
+
+func NewConnProp(orgId, envId, assetId string) *form.SubSchema {
+	return &form.SubSchema{
+		Type:        "select",
+		Title:       "Select a connection",
+		LoadOptions: CreateConnOptions(orgId, envId, assetId),
+	}
+}
+
+func ConnForm(cfg *config.CloudConfig, results *form.FormResult) error {
+	if results.Connection != "" {
+		return nil
+	}
+
+	prop := NewConnProp(results.Org, results.Env, results.Asset)
+	result, err := form.Run(form.NewModel(cfg, prop))
+	if err != nil {
+		return err
+	}
+	if result == "" {
+		return fmt.Errorf("You must select a connection")
+	}
+	results.Connection = result
+
+	return nil
+}
+
+func ConnDescribeForm(cfg *config.CloudConfig, results *form.FormResult) error {
+	forms := []form.FormFn{
+		libenv.EnvForm,
+		libasset.ResolveAssetForm,
+		ConnForm,
+	}
+
+	for _, formFn := range forms {
+		err := formFn(cfg, results)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -9,6 +9,31 @@ import (
 	"github.com/evertras/bubble-table/table"
 )
 
+// GetName - describes the connection as "outgoing => incoming"
+func GetName(conn cac.ConnectionOutput) string {
+	inc := ""
+	if conn.HasIncomingConnectionAsset() {
+		inc = libasset.GetName(*conn.IncomingConnectionAsset)
+	}
+	out := ""
+	if conn.HasOutgoingConnectionAsset() {
+		out = libasset.GetName(*conn.OutgoingConnectionAsset)
+	}
+	return fmt.Sprintf("%s => %s", out, inc)
+}
+
+func generateConnRowFromData(conn cac.ConnectionOutput) table.Row {
+	desc := ""
+	if conn.Description != nil {
+		desc = *conn.Description
+	}
+	return table.NewRow(table.RowData{
+		"id":          conn.Id,
+		"conn":        GetName(conn),
+		"description": desc,
+	})
+}
+
 // prints out a table of connections
 func ConnTable(connOutput interface{}) table.Model {
 	rows := make([]table.Row, 0)
@@ -16,36 +41,15 @@ func ConnTable(connOutput interface{}) table.Model {
 	switch data := connOutput.(type) {
 	case []cac.ConnectionOutput:
 		for _, conn := range data {
-			inc := ""
-			if conn.HasIncomingConnectionAsset() {
-				inc = libasset.GetName(*conn.IncomingConnectionAsset)
-			}
-			out := ""
-			if conn.HasOutgoingConnectionAsset() {
-				out = libasset.GetName(*conn.OutgoingConnectionAsset)
-			}
-			rows = append(rows, table.NewRow(table.RowData{
-				"id":   conn.Id,
-				"conn": fmt.Sprintf("%s => %s", out, inc),
-			}))
+			rows = append(rows, generateConnRowFromData(conn))
 		}
 	case *cac.ConnectionOutput:
-		inc := ""
-		out := ""
-		if data.HasIncomingConnectionAsset() {
-			inc = libasset.GetName(*data.IncomingConnectionAsset)
-		}
-		if data.HasOutgoingConnectionAsset() {
-			out = libasset.GetName(*data.OutgoingConnectionAsset)
-		}
-		rows = append(rows, table.NewRow(table.RowData{
-			"id":   data.Id,
-			"conn": fmt.Sprintf("%s => %s", out, inc),
-		}))
+		rows = append(rows, generateConnRowFromData(*data))
 	}
 
 	return table.New([]table.Column{
 		table.NewColumn("id", "Id", 40).WithStyle(common.DefaultRowStyle()),
 		table.NewColumn("conn", "Connection", 40).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("description", "Description", 40).WithStyle(common.DefaultRowStyle()),
 	}).WithRows(rows)
 }
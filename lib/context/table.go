@@ -0,0 +1,36 @@
+package libcontext
+
+import (
+	"github.com/aptible/cloud-cli/config"
+	"github.com/aptible/cloud-cli/ui/common"
+	"github.com/evertras/bubble-table/table"
+)
+
+// prints out a table of contexts, the current one is marked with a *
+func ContextTable(contexts []config.Context) table.Model {
+	rows := make([]table.Row, 0)
+
+	for _, ctx := range contexts {
+		current := ""
+		if ctx.Current {
+			current = "*"
+		}
+		rows = append(rows, table.NewRow(table.RowData{
+			"current":     current,
+			"name":        ctx.Name,
+			"api_domain":  ctx.Settings["api-domain"],
+			"auth_domain": ctx.Settings["auth-domain"],
+			"org":         ctx.Settings["org"],
+			"env":         ctx.Settings["env"],
+		}))
+	}
+
+	return table.New([]table.Column{
+		table.NewColumn("current", "", 3).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("name", "Name", 20).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("api_domain", "API Domain", 32).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("auth_domain", "Auth Domain", 24).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("org", "Organization Id", 38).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("env", "Environment Id", 38).WithStyle(common.DefaultRowStyle()),
+	}).WithRows(rows)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/aptible/cloud-cli/config"
 	"github.com/aptible/cloud-cli/lib/org"
+	libresolve "github.com/aptible/cloud-cli/lib/resolve"
 	"github.com/aptible/cloud-cli/ui/form"
 	"github.com/charmbracelet/bubbles/list"
 )
@@ -12,7 +13,7 @@ import (
 func CreateEnvOptions(orgId string) form.LoadOptionsFn {
 	options := []list.Item{}
 	return func(cfg *config.CloudConfig) ([]list.Item, error) {
-		orgs, err := cfg.Cc.ListEnvironments(orgId)
+		orgs, err := cfg.Cc.ListEnvironments(cfg.Ctx, orgId)
 		if err != nil {
 			return options, err
 		}
@@ -31,16 +32,60 @@ func NewEnvProp(orgId string) *form.SubSchema {
 	}
 }
 
+// ResolveEnv - the id of the environment ref names in the organization, ref
+// may be an id or a name
+func ResolveEnv(cfg *config.CloudConfig, orgId string, ref string) (string, error) {
+	return resolveEnv(cfg, orgId, ref, libresolve.One)
+}
+
+// ResolveEnvExact - like ResolveEnv for commands that destroy the
+// environment, names have to match exactly
+func ResolveEnvExact(cfg *config.CloudConfig, orgId string, ref string) (string, error) {
+	return resolveEnv(cfg, orgId, ref, libresolve.Exact)
+}
+
+func resolveEnv(cfg *config.CloudConfig, orgId string, ref string, resolve resolveFn) (string, error) {
+	if libresolve.IsId(ref) {
+		return ref, nil
+	}
+	envs, err := cfg.Cc.ListEnvironments(cfg.Ctx, orgId)
+	if err != nil {
+		return "", err
+	}
+	candidates := make([]libresolve.Candidate, 0, len(envs))
+	for _, env := range envs {
+		candidates = append(candidates, libresolve.Candidate{Id: env.Id, Name: env.Name})
+	}
+	env, err := resolve("environment", ref, candidates)
+	return env.Id, err
+}
+
+// resolveFn - libresolve.One or libresolve.Exact
+type resolveFn func(kind string, ref string, candidates []libresolve.Candidate) (libresolve.Candidate, error)
+
 func EnvForm(cfg *config.CloudConfig, results *form.FormResult) error {
+	return envForm(cfg, results, ResolveEnv)
+}
+
+// EnvDestroyForm - EnvForm for commands that destroy the environment, see
+// ResolveEnvExact
+func EnvDestroyForm(cfg *config.CloudConfig, results *form.FormResult) error {
+	return envForm(cfg, results, ResolveEnvExact)
+}
+
+func envForm(cfg *config.CloudConfig, results *form.FormResult, resolve func(cfg *config.CloudConfig, orgId string, ref string) (string, error)) error {
 	if err := liborg.OrgForm(cfg, results); err != nil {
 		return err
 	}
 
 	if results.Env != "" {
-		return nil
+		env, err := resolve(cfg, results.Org, results.Env)
+		results.Env = env
+		return err
 	}
 
 	prop := NewEnvProp(results.Org)
+	prop.Value = cfg.Vconfig.GetString("default-env")
 	result, err := form.Run(form.NewModel(cfg, prop))
 	if err != nil {
 		return err
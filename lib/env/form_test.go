@@ -0,0 +1,41 @@
+package libenv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aptible/cloud-cli/client/fake"
+	"github.com/aptible/cloud-cli/config"
+)
+
+func TestResolveEnv(t *testing.T) {
+	f := fake.NewSeeded()
+	f.Step = time.Millisecond
+	cfg := &config.CloudConfig{Cc: f, Ctx: context.Background()}
+
+	tests := []struct {
+		name      string
+		ref       string
+		wantRead  string
+		wantExact string
+	}{
+		{name: "id", ref: fake.DemoEnvId, wantRead: fake.DemoEnvId, wantExact: fake.DemoEnvId},
+		{name: "name", ref: "demo", wantRead: fake.DemoEnvId, wantExact: fake.DemoEnvId},
+		// destroying needs the name as it is, reading does not
+		{name: "name in another case", ref: "DEMO", wantRead: fake.DemoEnvId},
+		{name: "unknown", ref: "prod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveEnv(cfg, fake.DemoOrgId, tt.ref)
+			if got != tt.wantRead || (err == nil) != (tt.wantRead != "") {
+				t.Errorf("ResolveEnv(%s) = %q, %v, want %q", tt.ref, got, err, tt.wantRead)
+			}
+			got, err = ResolveEnvExact(cfg, fake.DemoOrgId, tt.ref)
+			if got != tt.wantExact || (err == nil) != (tt.wantExact != "") {
+				t.Errorf("ResolveEnvExact(%s) = %q, %v, want %q", tt.ref, got, err, tt.wantExact)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package libenv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+
+	"github.com/aptible/cloud-cli/client"
+	"github.com/aptible/cloud-cli/lib/op"
+)
+
+// Summary - an environment along with what is running inside of it
+type Summary struct {
+	Env         *cac.EnvironmentOutput
+	AssetCounts map[cac.AssetStatus]int
+	RecentOps   []cac.OperationOutput
+}
+
+// Summarize - describes the environment, counts its assets by status and
+// collects the `recent` newest operations across all of its assets
+func Summarize(ctx context.Context, cc client.CloudClient, orgId, envId string, recent int) (*Summary, error) {
+	env, err := cc.DescribeEnvironment(ctx, orgId, envId)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := cc.ListAssets(ctx, orgId, envId)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{Env: env, AssetCounts: map[cac.AssetStatus]int{}}
+	for _, asset := range assets {
+		summary.AssetCounts[asset.Status]++
+	}
+
+	ops, err := envOperations(ctx, cc, orgId, assets)
+	if err != nil {
+		return nil, err
+	}
+
+	ops = libop.FilterOps(ops, libop.Filter{})
+	if len(ops) > recent {
+		ops = ops[:recent]
+	}
+	summary.RecentOps = ops
+
+	return summary, nil
+}
+
+// maxAssetLookups - environments with up to this many assets have their
+// operations listed asset by asset
+const maxAssetLookups = 10
+
+// envOperations - the operations of assets.  Listing them asset by asset
+// only reads the environment's own history, but costs a request per asset,
+// which the rate limit would make crawl in large environments, so those
+// get a single listing for the whole org instead.  The api's order is not
+// documented, so neither can stop early at the newest operations.
+func envOperations(ctx context.Context, cc client.CloudClient, orgId string, assets []cac.AssetOutput) ([]cac.OperationOutput, error) {
+	ops := []cac.OperationOutput{}
+	if len(assets) <= maxAssetLookups {
+		for _, asset := range assets {
+			assetOps, err := cc.ListOperationsByAsset(ctx, orgId, asset.Id)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, assetOps...)
+		}
+		return ops, nil
+	}
+
+	inEnv := map[string]bool{}
+	for _, asset := range assets {
+		inEnv[asset.Id] = true
+	}
+	orgOps, err := cc.ListOperations(ctx, orgId)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range orgOps {
+		if inEnv[op.AssetId] {
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+// FormatAssetCounts - e.g. "DEPLOYED: 2, DEPLOYING: 1", or "none"
+func FormatAssetCounts(counts map[cac.AssetStatus]int) string {
+	if len(counts) == 0 {
+		return "none"
+	}
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, string(status))
+	}
+	sort.Strings(statuses)
+
+	parts := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		parts = append(parts, fmt.Sprintf("%s: %d", status, counts[cac.AssetStatus(status)]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,99 @@
+package libenv
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+
+	"github.com/aptible/cloud-cli/client/fake"
+)
+
+// countingClient - counts the operation listings Summarize makes
+type countingClient struct {
+	*fake.Client
+	byAsset int
+	org     int
+}
+
+func (c *countingClient) ListOperationsByAsset(ctx context.Context, orgId, assetId string) ([]cac.OperationOutput, error) {
+	c.byAsset++
+	return c.Client.ListOperationsByAsset(ctx, orgId, assetId)
+}
+
+func (c *countingClient) ListOperations(ctx context.Context, orgId string) ([]cac.OperationOutput, error) {
+	c.org++
+	return c.Client.ListOperations(ctx, orgId)
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name        string
+		extra       int
+		wantByAsset bool
+	}{
+		{name: "few assets", extra: 2, wantByAsset: true},
+		{name: "many assets", extra: maxAssetLookups + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := fake.NewSeeded()
+			f.Step = time.Millisecond
+			clock := time.Now()
+			f.Now = func() time.Time { return clock }
+
+			// an asset in another environment of the org
+			other, err := f.CreateEnvironment(ctx, fake.DemoOrgId, cac.EnvironmentInput{Name: "other"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			clock = clock.Add(time.Second)
+			otherAsset, err := f.CreateAsset(ctx, fake.DemoOrgId, other.Id, cac.AssetInput{Asset: "aws__rds__latest"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			created := []string{}
+			for i := 0; i < tt.extra; i++ {
+				clock = clock.Add(time.Second)
+				asset, err := f.CreateAsset(ctx, fake.DemoOrgId, fake.DemoEnvId, cac.AssetInput{
+					Asset:           "aws__rds__latest",
+					AssetParameters: map[string]interface{}{"name": fmt.Sprintf("db-%d", i)},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				created = append(created, asset.Id)
+			}
+			// the other environment's asset is the newest of all
+			clock = clock.Add(time.Second)
+			if _, _, err := f.UpdateAsset(ctx, fake.DemoOrgId, other.Id, otherAsset.Id, cac.AssetInput{Asset: "aws__rds__latest"}); err != nil {
+				t.Fatal(err)
+			}
+
+			cc := &countingClient{Client: f}
+			summary, err := Summarize(ctx, cc, fake.DemoOrgId, fake.DemoEnvId, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantByAsset && (cc.org != 0 || cc.byAsset == 0) {
+				t.Errorf("listed %d times by asset and %d for the org, want by asset only", cc.byAsset, cc.org)
+			}
+			if !tt.wantByAsset && (cc.org != 1 || cc.byAsset != 0) {
+				t.Errorf("listed %d times by asset and %d for the org, want one org listing", cc.byAsset, cc.org)
+			}
+			if len(summary.RecentOps) != 2 {
+				t.Fatalf("%d recent operations, want 2", len(summary.RecentOps))
+			}
+			// newest first, and only from this environment
+			newest := created[len(created)-1]
+			if summary.RecentOps[0].AssetId != newest || summary.RecentOps[1].AssetId != created[len(created)-2] {
+				t.Errorf("recent operations are for %s and %s, want the newest assets", summary.RecentOps[0].AssetId, summary.RecentOps[1].AssetId)
+			}
+		})
+	}
+}
@@ -6,7 +6,8 @@ import (
 	"github.com/evertras/bubble-table/table"
 )
 
-func safeString(str *string) string {
+// SafeString - the value or "unknown" when the api did not send one
+func SafeString(str *string) string {
 	if str == nil {
 		return "unknown"
 	}
@@ -24,14 +25,14 @@ func EnvTable(orgOutput interface{}) table.Model {
 			rows = append(rows, table.NewRow(table.RowData{
 				"id":             env.Id,
 				"name":           env.Name,
-				"aws_account_id": safeString(env.AwsAccountId),
+				"aws_account_id": SafeString(env.AwsAccountId),
 			}))
 		}
 	case *cac.EnvironmentOutput:
 		rows = append(rows, table.NewRow(table.RowData{
 			"id":             data.Id,
 			"name":           data.Name,
-			"aws_account_id": safeString(data.AwsAccountId),
+			"aws_account_id": SafeString(data.AwsAccountId),
 		}))
 	}
 
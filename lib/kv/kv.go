@@ -0,0 +1,36 @@
+package libkv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Parse - turns repeated `key=value` flags into a map, the value may contain `=`
+func Parse(pairs []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid value %q, expected key=value", pair)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// Merge - copy of current with the updates applied, an empty value removes the key
+func Merge(current map[string]interface{}, updates map[string]string) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for key, value := range current {
+		merged[key] = value
+	}
+	for key, value := range updates {
+		if value == "" {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
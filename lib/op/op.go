@@ -0,0 +1,129 @@
+package libop
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cac "github.com/aptible/cloud-api-clients/clients/go"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// IsDone - whether the operation reached a status it will not leave
+func IsDone(op cac.OperationOutput) bool {
+	status := op.Status.Get()
+	if status == nil {
+		return false
+	}
+	switch *status {
+	case cac.OPERATIONSTATUS_COMPLETE, cac.OPERATIONSTATUS_CANCELED, cac.OPERATIONSTATUS_FAILED:
+		return true
+	default:
+		return false
+	}
+}
+
+// TypeName - the operation's type, blank when the api did not send one
+func TypeName(op cac.OperationOutput) string {
+	if op.OperationType.Get() == nil {
+		return ""
+	}
+	return string(*op.OperationType.Get())
+}
+
+// StatusName - the operation's status, blank when the api did not send one
+func StatusName(op cac.OperationOutput) string {
+	if op.Status.Get() == nil {
+		return ""
+	}
+	return string(*op.Status.Get())
+}
+
+// WaitForOperation - polls the operations of an asset every interval until
+// the operation is done or ctx is cancelled
+func WaitForOperation(ctx context.Context, cc client.CloudClient, orgId, assetId, opId string, interval time.Duration) (*cac.OperationOutput, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ops, err := cc.ListOperationsByAsset(ctx, orgId, assetId)
+		if err != nil {
+			return nil, err
+		}
+		for _, op := range ops {
+			if op.Id == opId && IsDone(op) {
+				return &op, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+var (
+	opTypes    = []cac.OperationType{cac.OPERATIONTYPE_APPLY, cac.OPERATIONTYPE_DESTROY}
+	opStatuses = []cac.OperationStatus{
+		cac.OPERATIONSTATUS_PENDING,
+		cac.OPERATIONSTATUS_IN_PROGRESS,
+		cac.OPERATIONSTATUS_PAUSED,
+		cac.OPERATIONSTATUS_COMPLETE,
+		cac.OPERATIONSTATUS_CANCELED,
+		cac.OPERATIONSTATUS_FAILED,
+	}
+)
+
+// Filter - narrows down a list of operations, blank fields match anything
+type Filter struct {
+	AssetId string
+	Type    string
+	Status  string
+}
+
+// Validate - rejects types and statuses the api does not know about
+func (f Filter) Validate() error {
+	if f.Type != "" && !containsFold(opTypes, f.Type) {
+		return fmt.Errorf("unknown operation type %q, expected one of %v", f.Type, opTypes)
+	}
+	if f.Status != "" && !containsFold(opStatuses, f.Status) {
+		return fmt.Errorf("unknown operation status %q, expected one of %v", f.Status, opStatuses)
+	}
+	return nil
+}
+
+func containsFold[T ~string](values []T, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(string(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterOps - the operations matching the filter, newest first.  Only ops
+// are sorted, a paged list is sorted one page at a time.
+func FilterOps(ops []cac.OperationOutput, filter Filter) []cac.OperationOutput {
+	filtered := []cac.OperationOutput{}
+	for _, op := range ops {
+		if filter.AssetId != "" && op.AssetId != filter.AssetId {
+			continue
+		}
+		if filter.Type != "" && !strings.EqualFold(TypeName(op), filter.Type) {
+			continue
+		}
+		if filter.Status != "" && !strings.EqualFold(StatusName(op), filter.Status) {
+			continue
+		}
+		filtered = append(filtered, op)
+	}
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+	})
+	return filtered
+}
@@ -1,6 +1,8 @@
 package libop
 
 import (
+	"time"
+
 	cac "github.com/aptible/cloud-api-clients/clients/go"
 	"github.com/aptible/cloud-cli/ui/common"
 	"github.com/evertras/bubble-table/table"
@@ -8,7 +10,7 @@ import (
 
 // colorizeOperationFromStatus - common utility for assets to colorize rows in CLI based on asset status
 func colorizeOperationFromStatus(operation cac.OperationOutput, row table.Row) table.Row {
-	switch *operation.Status.Get() {
+	switch cac.OperationStatus(StatusName(operation)) {
 	case cac.OPERATIONSTATUS_COMPLETE:
 		return row.WithStyle(common.ActiveRowStyle())
 	case cac.OPERATIONSTATUS_IN_PROGRESS,
@@ -22,12 +24,47 @@ func colorizeOperationFromStatus(operation cac.OperationOutput, row table.Row) t
 	}
 }
 
+// FormatTime - local timestamp or blank when the api did not send one
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// Duration - how long the operation ran, or has been running so far
+func Duration(op cac.OperationOutput) time.Duration {
+	if op.CreatedAt.IsZero() {
+		return 0
+	}
+	end := time.Now()
+	if IsDone(op) && !op.UpdatedAt.IsZero() {
+		end = op.UpdatedAt
+	}
+	return end.Sub(op.CreatedAt).Round(time.Second)
+}
+
+// ErrorMessage - why the operation failed, blank when it did not
+func ErrorMessage(op cac.OperationOutput) string {
+	if op.ErrorMessage == nil {
+		return ""
+	}
+	return *op.ErrorMessage
+}
+
 // generateAssetRowFromData - generate a common table row for assets
 func generateOpRowFromData(op cac.OperationOutput) table.Row {
+	duration := ""
+	if d := Duration(op); d > 0 {
+		duration = d.String()
+	}
 	row := table.NewRow(table.RowData{
-		"id":     op.Id,
-		"type":   *op.OperationType.Get(),
-		"status": *op.Status.Get(),
+		"id":       op.Id,
+		"type":     TypeName(op),
+		"status":   StatusName(op),
+		"created":  FormatTime(op.CreatedAt),
+		"duration": duration,
+		"error":    ErrorMessage(op),
 	})
 	return colorizeOperationFromStatus(op, row)
 }
@@ -47,7 +84,10 @@ func OpTable(orgOutput interface{}) table.Model {
 
 	return table.New([]table.Column{
 		table.NewColumn("id", "Id", 40).WithStyle(common.DefaultRowStyle()),
-		table.NewColumn("type", "Type", 20).WithStyle(common.DefaultRowStyle()),
-		table.NewColumn("status", "Status", 40).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("type", "Type", 10).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("status", "Status", 14).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("created", "Created", 21).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("duration", "Duration", 10).WithStyle(common.DefaultRowStyle()),
+		table.NewColumn("error", "Error", 40).WithStyle(common.DefaultRowStyle()),
 	}).WithRows(rows)
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/aptible/cloud-cli/config"
+	libresolve "github.com/aptible/cloud-cli/lib/resolve"
 	"github.com/aptible/cloud-cli/ui/form"
 	"github.com/charmbracelet/bubbles/list"
 )
@@ -19,7 +20,7 @@ func NewOrgProp() *form.SubSchema {
 func CreateOrgOptions() form.LoadOptionsFn {
 	options := []list.Item{}
 	return func(cfg *config.CloudConfig) ([]list.Item, error) {
-		orgs, err := cfg.Cc.ListOrgs()
+		orgs, err := cfg.Cc.ListOrgs(cfg.Ctx)
 		if err != nil {
 			return options, err
 		}
@@ -30,12 +31,32 @@ func CreateOrgOptions() form.LoadOptionsFn {
 	}
 }
 
+// ResolveOrg - the id of the organization ref names, ref may be an id or a name
+func ResolveOrg(cfg *config.CloudConfig, ref string) (string, error) {
+	if libresolve.IsId(ref) {
+		return ref, nil
+	}
+	orgs, err := cfg.Cc.ListOrgs(cfg.Ctx)
+	if err != nil {
+		return "", err
+	}
+	candidates := make([]libresolve.Candidate, 0, len(orgs))
+	for _, org := range orgs {
+		candidates = append(candidates, libresolve.Candidate{Id: org.Id, Name: org.Name})
+	}
+	org, err := libresolve.One("organization", ref, candidates)
+	return org.Id, err
+}
+
 func OrgForm(cfg *config.CloudConfig, results *form.FormResult) error {
 	if results.Org != "" {
-		return nil
+		org, err := ResolveOrg(cfg, results.Org)
+		results.Org = org
+		return err
 	}
 
 	prop := NewOrgProp()
+	prop.Value = cfg.Vconfig.GetString("default-org")
 	result, err := form.Run(form.NewModel(cfg, prop))
 	if err != nil {
 		return err
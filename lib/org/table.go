@@ -6,6 +6,22 @@ import (
 	"github.com/evertras/bubble-table/table"
 )
 
+// GetAwsOu - the organization's AWS OU, blank until one is assigned
+func GetAwsOu(org cac.OrganizationOutput) string {
+	if org.AwsOu == nil {
+		return ""
+	}
+	return *org.AwsOu
+}
+
+func generateOrgRowFromData(org cac.OrganizationOutput) table.Row {
+	return table.NewRow(table.RowData{
+		"id":     org.Id,
+		"name":   org.Name,
+		"aws_ou": GetAwsOu(org),
+	})
+}
+
 // prints out a table of organizations
 func OrgTable(orgOutput interface{}) table.Model {
 	rows := make([]table.Row, 0)
@@ -13,18 +29,10 @@ func OrgTable(orgOutput interface{}) table.Model {
 	switch data := orgOutput.(type) {
 	case []cac.OrganizationOutput:
 		for _, org := range data {
-			rows = append(rows, table.NewRow(table.RowData{
-				"id":     org.Id,
-				"name":   org.Name,
-				"aws_ou": *org.AwsOu,
-			}))
+			rows = append(rows, generateOrgRowFromData(org))
 		}
 	case *cac.OrganizationOutput:
-		rows = append(rows, table.NewRow(table.RowData{
-			"id":     data.Id,
-			"name":   data.Name,
-			"aws_ou": *data.AwsOu,
-		}))
+		rows = append(rows, generateOrgRowFromData(*data))
 	}
 
 	return table.New([]table.Column{
@@ -0,0 +1,162 @@
+package libresolve
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// maxSuggestions - how many similar names a not found error lists
+const maxSuggestions = 3
+
+var idPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// IsId - whether ref is shaped like an api id.  Ids are passed through
+// without a lookup so scripts pay no extra request for them.
+func IsId(ref string) bool {
+	return idPattern.MatchString(ref)
+}
+
+// Candidate - one of the resources a reference may point to
+type Candidate struct {
+	Id   string
+	Name string
+}
+
+func (c Candidate) String() string {
+	return fmt.Sprintf("%s (%s)", c.Name, c.Id)
+}
+
+// One - the candidate whose id is ref, or else the only one named ref.  A
+// name that matches nobody exactly may still match one candidate when case
+// is ignored.  kind names the resource in errors, e.g. "organization".
+func One(kind string, ref string, candidates []Candidate) (Candidate, error) {
+	return one(kind, ref, candidates, true)
+}
+
+// Exact - like One without ignoring case, for commands that destroy what
+// they find
+func Exact(kind string, ref string, candidates []Candidate) (Candidate, error) {
+	return one(kind, ref, candidates, false)
+}
+
+func one(kind string, ref string, candidates []Candidate, fold bool) (Candidate, error) {
+	for _, c := range candidates {
+		if c.Id == ref {
+			return c, nil
+		}
+	}
+
+	matches := named(candidates, func(name string) bool { return name == ref })
+	if len(matches) == 0 && fold {
+		matches = named(candidates, func(name string) bool { return strings.EqualFold(name, ref) })
+	}
+	switch len(matches) {
+	case 0:
+		msg := fmt.Sprintf("no %s with the id or name %q", kind, ref)
+		if near := Suggest(ref, candidates); len(near) > 0 {
+			msg += fmt.Sprintf(", did you mean %s?", join(near, " or "))
+		}
+		return Candidate{}, &client.APIError{Kind: client.KindNotFound, Message: msg}
+	case 1:
+		return matches[0], nil
+	default:
+		return Candidate{}, &client.APIError{
+			Kind: client.KindValidation,
+			Message: fmt.Sprintf(
+				"%d %ss are named %q, pass the id of one of: %s",
+				len(matches), kind, ref, join(matches, ", "),
+			),
+		}
+	}
+}
+
+func named(candidates []Candidate, match func(name string) bool) []Candidate {
+	matches := []Candidate{}
+	for _, c := range candidates {
+		if match(c.Name) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+func join(candidates []Candidate, sep string) string {
+	names := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		names = append(names, c.String())
+	}
+	return strings.Join(names, sep)
+}
+
+// Suggest - the candidates whose names are a few edits away from ref,
+// closest first
+func Suggest(ref string, candidates []Candidate) []Candidate {
+	type scored struct {
+		Candidate
+		distance int
+	}
+	// roughly one typo for every three letters, and at least one
+	limit := len([]rune(ref)) / 3
+	if limit < 1 {
+		limit = 1
+	}
+
+	near := []scored{}
+	for _, c := range candidates {
+		d := Distance(strings.ToLower(ref), strings.ToLower(c.Name))
+		if d <= limit {
+			near = append(near, scored{c, d})
+		}
+	}
+	sort.SliceStable(near, func(i, j int) bool {
+		return near[i].distance < near[j].distance
+	})
+
+	suggestions := []Candidate{}
+	for i := 0; i < len(near) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, near[i].Candidate)
+	}
+	return suggestions
+}
+
+// Distance - the edit distance between a and b: how many single letter
+// insertions, deletions, substitutions and swaps of neighbouring letters
+// turn one into the other, swaps being the most common typo
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,166 @@
+package libresolve
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+const (
+	idProd    = "00000000-0000-4000-8000-00000000000a"
+	idStaging = "00000000-0000-4000-8000-00000000000b"
+	idDemo1   = "00000000-0000-4000-8000-00000000000c"
+	idDemo2   = "00000000-0000-4000-8000-00000000000d"
+	idUpper   = "00000000-0000-4000-8000-00000000000e"
+)
+
+var candidates = []Candidate{
+	{Id: idProd, Name: "production"},
+	{Id: idStaging, Name: "staging"},
+	{Id: idDemo1, Name: "demo"},
+	{Id: idDemo2, Name: "demo"},
+	{Id: idUpper, Name: "Reports"},
+}
+
+func TestIsId(t *testing.T) {
+	tests := map[string]bool{
+		idProd:                                  true,
+		"00000000-0000-4000-8000-00000000000A":  true,
+		"00000000-0000-4000-8000-00000000000":   false,
+		"00000000-0000-4000-8000-00000000000g":  false,
+		"production":                            false,
+		"":                                      false,
+		" 00000000-0000-4000-8000-00000000000a": false,
+	}
+	for ref, want := range tests {
+		if got := IsId(ref); got != want {
+			t.Errorf("IsId(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
+
+func TestOne(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolve  func(kind string, ref string, candidates []Candidate) (Candidate, error)
+		ref      string
+		wantId   string
+		wantKind client.ErrorKind
+		// wantMsg - part of the error message
+		wantMsg string
+	}{
+		{name: "id", resolve: One, ref: idStaging, wantId: idStaging},
+		{name: "id of a duplicate name", resolve: One, ref: idDemo2, wantId: idDemo2},
+		{name: "name", resolve: One, ref: "production", wantId: idProd},
+		{name: "name ignoring case", resolve: One, ref: "REPORTS", wantId: idUpper},
+		{name: "ambiguous name", resolve: One, ref: "demo", wantKind: client.KindValidation, wantMsg: idDemo1},
+		{name: "not found with a suggestion", resolve: One, ref: "prodution", wantKind: client.KindNotFound, wantMsg: "did you mean production"},
+		{name: "not found", resolve: One, ref: "qa-east-west", wantKind: client.KindNotFound, wantMsg: `"qa-east-west"`},
+		{name: "exact id", resolve: Exact, ref: idStaging, wantId: idStaging},
+		{name: "exact name", resolve: Exact, ref: "Reports", wantId: idUpper},
+		{name: "exact does not ignore case", resolve: Exact, ref: "reports", wantKind: client.KindNotFound, wantMsg: "did you mean Reports"},
+		{name: "exact ambiguous name", resolve: Exact, ref: "demo", wantKind: client.KindValidation, wantMsg: idDemo2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.resolve("environment", tt.ref, candidates)
+			if tt.wantKind == client.KindUnknown {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got.Id != tt.wantId {
+					t.Errorf("resolved %s, want %s", got.Id, tt.wantId)
+				}
+				return
+			}
+
+			var apiErr *client.APIError
+			if !errors.As(err, &apiErr) || apiErr.Kind != tt.wantKind {
+				t.Fatalf("err = %v, want kind %d", err, tt.wantKind)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("err = %q, want it to mention %s", err, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestOneExitCodes(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+	}{
+		{ref: "demo", want: 6},
+		{ref: "nothing-like-it", want: 3},
+	}
+	for _, tt := range tests {
+		_, err := One("environment", tt.ref, candidates)
+		if got := client.ExitCode(err); got != tt.want {
+			t.Errorf("exit code for %q = %d, want %d", tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	names := func(names ...string) []Candidate {
+		candidates := []Candidate{}
+		for _, name := range names {
+			candidates = append(candidates, Candidate{Id: name, Name: name})
+		}
+		return candidates
+	}
+
+	tests := []struct {
+		name       string
+		ref        string
+		candidates []Candidate
+		want       []string
+	}{
+		{name: "typo", ref: "stagign", candidates: names("production", "staging"), want: []string{"staging"}},
+		{name: "closest first", ref: "demo-1", candidates: names("demo-1xy", "demo-11", "demo-2"), want: []string{"demo-11", "demo-2", "demo-1xy"}},
+		{name: "at most three", ref: "app-1", candidates: names("app-2", "app-3", "app-4", "app-5"), want: []string{"app-2", "app-3", "app-4"}},
+		{name: "ignores case", ref: "STAGING", candidates: names("staging"), want: []string{"staging"}},
+		{name: "short refs allow one edit", ref: "db", candidates: names("dc", "xyz"), want: []string{"dc"}},
+		{name: "too far", ref: "production", candidates: names("staging", "demo"), want: []string{}},
+		{name: "no candidates", ref: "production", candidates: nil, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, c := range Suggest(tt.ref, tt.candidates) {
+				got = append(got, c.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q) = %v, want %v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "", b: "abc", want: 3},
+		{a: "abc", b: "", want: 3},
+		{a: "same", b: "same", want: 0},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "ab", b: "ba", want: 1},
+		{a: "staging", b: "stagign", want: 1},
+		{a: "ca", b: "abc", want: 3},
+		{a: "café", b: "cafe", want: 1},
+	}
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Distance(tt.b, tt.a); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package assetui
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"reflect"
@@ -73,6 +74,7 @@ func (m Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "q", "esc", "ctrl+c":
+			m.config.Cancel()
 			return m, tea.Quit
 		}
 	case fetch.SuccessMsg:
@@ -83,8 +85,8 @@ func (m Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 	case statusInit:
 		m.status = statusReady
 		opMsg := "refreshing"
-		m.fetchOps = fetch.NewModelLooper(opMsg, 3*time.Second, func() (interface{}, error) {
-			return m.config.Cc.ListOperationsByAsset(m.orgId, m.asset.Id)
+		m.fetchOps = fetch.NewModelLooper(m.config.Ctx, opMsg, 3*time.Second, func(ctx context.Context) (interface{}, error) {
+			return m.config.Cc.ListOperationsByAsset(ctx, m.orgId, m.asset.Id)
 		})
 		return m, m.fetchOps.Init()
 	}
@@ -1,8 +1,8 @@
 package fetch
 
 import (
+	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/aptible/cloud-cli/ui/common"
@@ -32,33 +32,42 @@ type Model struct {
 	spinner loader.Model
 	Result  interface{}
 	Err     error
+	ctx     context.Context
+	cancel  context.CancelFunc
 	io      Fx
 	status  state
 	styles  common.Styles
 	Loop    time.Duration
+	// Transient - quit as soon as the request succeeds and leave nothing on
+	// screen, for requests whose result is printed right away
+	Transient bool
 }
 
-type Fx func() (dataModel interface{}, error error)
+// Fx - the request to run, ctx is cancelled when the user quits the model
+type Fx func(ctx context.Context) (dataModel interface{}, error error)
 
-func NewModel(text string, io Fx) Model {
+func NewModel(ctx context.Context, text string, io Fx) Model {
 	s := loader.NewModel(text)
+	ctx, cancel := context.WithCancel(ctx)
 	return Model{
 		spinner: s,
+		ctx:     ctx,
+		cancel:  cancel,
 		io:      io,
 		status:  ready,
 		styles:  common.MainStyles,
 	}
 }
 
-func NewModelLooper(text string, loop time.Duration, io Fx) Model {
-	mdl := NewModel(text, io)
+func NewModelLooper(ctx context.Context, text string, loop time.Duration, io Fx) Model {
+	mdl := NewModel(ctx, text, io)
 	mdl.Loop = loop
 	return mdl
 }
 
-func create(fx Fx) tea.Cmd {
+func create(ctx context.Context, fx Fx) tea.Cmd {
 	return func() tea.Msg {
-		res, err := fx()
+		res, err := fx(ctx)
 		if err != nil {
 			return err
 		}
@@ -75,6 +84,9 @@ func (m Model) FetchCmd() tea.Cmd {
 
 func (m Model) successCmd() tea.Cmd {
 	return func() tea.Msg {
+		if m.Transient {
+			return tea.Quit()
+		}
 		if m.Loop == 0 {
 			time.Sleep(300 * time.Millisecond)
 			return tea.Quit()
@@ -95,6 +107,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "q", "esc", "ctrl+c":
 			m.status = quitting
+			m.cancel()
 			return m, tea.Quit
 		default:
 			return m, nil
@@ -107,7 +120,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case fetchMsg:
 		m.status = submitting
-		return m, create(m.io)
+		return m, create(m.ctx, m.io)
 
 	case SuccessMsg:
 		m.status = success
@@ -115,8 +128,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, m.successCmd()
 
 	case errMsg:
+		// rendering the error is left to the command, see cmd.Execute
 		m.Err = msg
-		fmt.Printf("Error encountered: %s\n", msg)
 		return m, tea.Quit
 	}
 
@@ -125,12 +138,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) View() string {
 	if m.Err != nil {
-		return m.Err.Error()
+		return ""
 	}
 	str := ""
 	if m.status == submitting {
 		str += m.spinner.View()
-	} else if m.status == success {
+	} else if m.status == success && !m.Transient {
 		str += fmt.Sprintf("%s success!", m.styles.Checkmark.String())
 	} else if m.status == quitting {
 		str += "\n"
@@ -138,13 +151,21 @@ func (m Model) View() string {
 	return str
 }
 
+// Cancel - releases the model's context, WithOutput calls it once the
+// program exits so a request that completed does not leak it
+func (m Model) Cancel() {
+	m.cancel()
+}
+
 func Any(model tea.Model) error {
-	p := tea.NewProgram(model)
-	err := p.Start()
+	_, err := WithOutput(model)
 	return err
 }
 
 func WithOutput(model tea.Model) (*Model, error) {
+	if m, ok := model.(Model); ok {
+		defer m.Cancel()
+	}
 	p := tea.NewProgram(model)
 	m, err := p.StartReturningModel()
 	if err != nil {
@@ -152,9 +173,12 @@ func WithOutput(model tea.Model) (*Model, error) {
 	}
 
 	n := m.(Model)
+	if n.status == quitting {
+		// the user bailed out before the request finished
+		return nil, n.ctx.Err()
+	}
 	if n.Err != nil {
-		n.Update(n.Err) // this also quites the program
-		os.Exit(1)
+		return nil, n.Err
 	}
 
 	return &n, nil
@@ -0,0 +1,37 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aptible/cloud-cli/client"
+)
+
+// Pages - reads pager one page at a time behind a spinner and hands every
+// page to render as soon as it arrives, first is set for the first page that
+// has items.  Returns how many items were read.
+func Pages[T any](ctx context.Context, text string, pager *client.Pager[T], render func(items []T, first bool)) (int, error) {
+	count := 0
+	for page := 1; !pager.Done(); page++ {
+		msg := text
+		if page > 1 {
+			msg = fmt.Sprintf("%s (page %d)", text, page)
+		}
+		model := NewModel(ctx, msg, func(ctx context.Context) (interface{}, error) {
+			return pager.Next(ctx)
+		})
+		model.Transient = true
+
+		result, err := WithOutput(model)
+		if err != nil {
+			return count, err
+		}
+		items := result.Result.([]T)
+		if len(items) == 0 {
+			continue
+		}
+		render(items, count == 0)
+		count += len(items)
+	}
+	return count, nil
+}
@@ -2,6 +2,7 @@ package form
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/aptible/cloud-cli/config"
 	"github.com/aptible/cloud-cli/ui/common"
@@ -49,6 +50,11 @@ func NewModel(cfg *config.CloudConfig, schema *SubSchema) *Model {
 	ti := textinput.New()
 	ti.CharLimit = 156
 	ti.Width = 50
+	ti.SetValue(schema.Value)
+	if schema.Secret {
+		ti.EchoMode = textinput.EchoPassword
+		ti.EchoCharacter = '•'
+	}
 
 	model := &Model{
 		styles:  common.DefaultStyles(),
@@ -59,10 +65,35 @@ func NewModel(cfg *config.CloudConfig, schema *SubSchema) *Model {
 		list:    list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0),
 		input:   ti,
 	}
-	model.list.Title = schema.Title
+	model.list.Title = model.title()
 	return model
 }
 
+// title - the schema's title, prefixed with the active context so nobody
+// picks a production resource thinking they are in staging
+func (m Model) title() string {
+	if name := config.ActiveContext(); name != "" {
+		return fmt.Sprintf("[%s] %s", name, m.schema.Title)
+	}
+	return m.schema.Title
+}
+
+// markCurrent - labels the option holding value "(current)"
+func markCurrent(options []list.Item, value string) []list.Item {
+	if value == "" {
+		return options
+	}
+	marked := make([]list.Item, 0, len(options))
+	for _, item := range options {
+		if option, ok := item.(FormOption); ok && option.Value == value {
+			option.Label += " (current)"
+			item = option
+		}
+		marked = append(marked, item)
+	}
+	return marked
+}
+
 func (m Model) fetchOptions() tea.Cmd {
 	return func() tea.Msg {
 		options, err := m.schema.LoadOptions(m.config)
@@ -120,7 +151,12 @@ func (m Model) Update(message tea.Msg) (tea.Model, tea.Cmd) {
 			return m, valueEntered(val)
 		} else {
 			m.status = statusReady
-			m.list.SetItems(msg.Options)
+			m.list.SetItems(markCurrent(msg.Options, m.schema.Value))
+			for i, item := range msg.Options {
+				if option, ok := item.(FormOption); ok && option.Value == m.schema.Value {
+					m.list.Select(i)
+				}
+			}
 		}
 	case valueEnteredMsg:
 		m.status = statusValueEntered
@@ -169,14 +205,18 @@ func (m Model) View() string {
 	} else if m.status == statusLoadingOptions {
 		s += m.spinner.View()
 	} else if m.status == statusValueEntered {
+		result := m.Result
+		if m.schema.Secret {
+			result = strings.Repeat("•", len([]rune(result)))
+		}
 		s += fmt.Sprintf(
 			"%s: %s%s\n",
-			m.schema.Title,
-			m.styles.SuccessText.Render(m.Result),
+			m.title(),
+			m.styles.SuccessText.Render(result),
 			m.styles.InfoText.Render(m.metaDesc),
 		)
 	} else if m.status == statusUserInput {
-		s += fmt.Sprintf("%s: %s", m.schema.Title, m.input.View())
+		s += fmt.Sprintf("%s: %s", m.title(), m.input.View())
 	}
 
 	return s
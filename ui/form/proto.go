@@ -16,6 +16,7 @@ type FormResult struct {
 	InAsset       string
 	OutAsset      string
 	Asset         string
+	Connection    string
 	Description   string
 }
 
@@ -36,5 +37,10 @@ type SubSchema struct {
 	Title       string
 	Type        string
 	LoadOptions LoadOptionsFn
-	Err         error
+	// Value - initial value of an input, e.g. the current setting being
+	// edited, or the option a select starts on, marked "(current)"
+	Value string
+	// Secret - mask what is typed into an input, e.g. passwords
+	Secret bool
+	Err    error
 }